@@ -1,12 +1,13 @@
 package reg
 
 import (
-	"fmt"
+	"reflect"
 	"sync"
 )
 
 // Alias provides a package-specific alias and Registry combination.
 // This simplifies registration of types in a package with a common alias.
+// Errors from the Registry are returned unchanged.
 type Alias struct {
 	Registry
 	alias    string
@@ -30,19 +31,91 @@ func NewAlias(alias string, registry Registry) *Alias {
 // Generates the embedded Registry.Alias() call with first use.
 // Actual registration passed along to package registry object.
 func (a *Alias) Register(example interface{}) error {
+	if err := a.addAlias(example); err != nil {
+		return err
+	}
+
+	return a.Registry.Register(example)
+}
+
+// RegisterAs registers a type by providing an explicit name and an example object.
+// Generates the embedded Registry.Alias() call with first use.
+// Actual registration passed along to package registry object.
+func (a *Alias) RegisterAs(name string, example interface{}) error {
+	if err := a.addAlias(example); err != nil {
+		return err
+	}
+
+	return registerAs(a.Registry, name, example)
+}
+
+// RegisterFactory registers a factory function for the type of the specified example object.
+// Generates the embedded Registry.Alias() call with first use unless the example is a type name.
+// Actual registration passed along to package registry object.
+func (a *Alias) RegisterFactory(example interface{}, factory func() interface{}) error {
+	if _, byName := example.(string); !byName {
+		if err := a.addAlias(example); err != nil {
+			return err
+		}
+	}
+
+	return registerFactory(a.Registry, example, factory)
+}
+
+// RegisterPrototype registers a prototype object copied by Make to create new instances.
+// Generates the embedded Registry.Alias() call with first use.
+// Actual registration passed along to package registry object.
+func (a *Alias) RegisterPrototype(name string, prototype interface{}) error {
+	if err := a.addAlias(prototype); err != nil {
+		return err
+	}
+
+	return registerPrototype(a.Registry, name, prototype)
+}
+
+// RemoveAlias removes an alias and the aliased names created from it.
+// Actual removal passed along to package registry object.
+func (a *Alias) RemoveAlias(alias string, keepNames bool) error {
+	return removeAlias(a.Registry, alias, keepNames)
+}
+
+// ReplaceAlias redefines an existing alias to refer to the package of the example object.
+// Actual replacement passed along to package registry object.
+func (a *Alias) ReplaceAlias(alias string, example interface{}, keepNames bool) error {
+	return replaceAlias(a.Registry, alias, example, keepNames)
+}
+
+// TypeFor returns the registered type with the specified name.
+func (a *Alias) TypeFor(name string) (reflect.Type, error) {
+	return typeFor(a.Registry, name)
+}
+
+func (a *Alias) findType(name string) (reflect.Type, error) {
+	return findType(a.Registry, name)
+}
+
+// Names returns the current names of all registered types in sorted order
+// or nil if the embedded Registry doesn't list names.
+func (a *Alias) Names() []string {
+	names, _ := a.names()
+	return names
+}
+
+func (a *Alias) names() ([]string, error) {
+	return namesOf(a.Registry)
+}
+
+// addAlias adds the alias for the package of the example object if it hasn't been done yet.
+func (a *Alias) addAlias(example interface{}) error {
 	if !a.aliased {
 		a.updating.Lock()
+		defer a.updating.Unlock()
 		if !a.aliased {
 			if err := a.AddAlias(a.alias, example); err != nil {
-				return fmt.Errorf("register alias: %w", err)
+				return err
 			}
 			a.aliased = true
 		}
-		a.updating.Unlock()
-	}
-
-	if err := a.Registry.Register(example); err != nil {
-		return fmt.Errorf("register example: %w", err)
 	}
 
 	return nil
@@ -35,3 +35,37 @@ func TestAlias(t *testing.T) {
 	assert.Len(t, reg.byName, 2)
 	require.Error(t, alias.Register(&example3{}))
 }
+
+func TestAliasRegisterFactory(t *testing.T) {
+	alias := NewAlias("factory", NewRegistry())
+	require.NoError(t, alias.RegisterFactory(&Alpha{}, func() interface{} { return &Alpha{Name: "Factory"} }))
+	assert.True(t, alias.aliased)
+	item, err := alias.Make("[factory]Alpha")
+	require.NoError(t, err)
+	assert.Equal(t, &Alpha{Name: "Factory"}, item)
+	require.NoError(t, alias.RegisterFactory("[factory]Alpha", func() interface{} { return &Alpha{Name: "Again"} }))
+	item, err = alias.Make("[factory]Alpha")
+	require.NoError(t, err)
+	assert.Equal(t, &Alpha{Name: "Again"}, item)
+}
+
+func TestAliasPrivateTypes(t *testing.T) {
+	alias := NewAlias(aliasName, NewRegistry(WithPrivateTypes()))
+	require.NoError(t, alias.Register(&example3{}))
+	name, err := alias.NameFor(&example3{})
+	require.NoError(t, err)
+	assert.Equal(t, "[test]example3", name)
+	item, err := alias.Make(name)
+	require.NoError(t, err)
+	assert.IsType(t, &example3{}, item)
+
+	// Explicit names allow private types without the option.
+	alias = NewAlias(aliasName, NewRegistry())
+	require.NoError(t, alias.RegisterAs("example", &example3{}))
+	item, err = alias.Make("example")
+	require.NoError(t, err)
+	assert.IsType(t, &example3{}, item)
+	item, err = alias.Make("[test]example3")
+	require.NoError(t, err)
+	assert.IsType(t, &example3{}, item)
+}
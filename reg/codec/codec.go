@@ -0,0 +1,73 @@
+// Package codec provides polymorphic serialization of registered types via a common Codec interface.
+//
+// Every Codec serializes an item into an envelope that contains
+// the name of the item's type (from reg.Registry.NameFor) and the item data.
+// Deserialization reads the type name from the envelope,
+// creates a new instance via reg.Registry.Make, and fills it from the item data.
+//
+// The envelope contract shared by all Codec implementations is:
+//
+//   - Encode of a registered item produces data that Decode turns back into
+//     a new item of the same type with the same field values.
+//   - TypeName returns the name of the encoded type without decoding the item.
+//   - The type name in the envelope is the current name from reg.Registry.NameFor.
+//   - Encode of a nil item returns ErrNilItem.
+//   - Encode of an item with an unregistered type returns an error.
+//     Composite and predeclared types (e.g. float64 or []int) are unregistered
+//     unless the Registry was created with reg.WithDerivedNames.
+//   - Decode of an envelope with an unregistered type name returns an error.
+//   - Decode or TypeName of an envelope without a type name returns ErrNoTypeName.
+//
+// Codecs created with NewLenient decode envelopes with unregistered type names
+// into reg.Unknown placeholders instead of returning an error.
+//
+// Implementations are provided for JSON, XML, and encoding/gob.
+// The codectest package provides a conformance test for the format-independent rules
+// which can be used to test other Codec implementations.
+package codec
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+// Codec encodes registered items with their type names and decodes them into new instances.
+type Codec interface {
+	// Encode the item into an envelope with its type name.
+	Encode(item interface{}) ([]byte, error)
+
+	// Decode the envelope into a new instance of the named type.
+	Decode(data []byte) (interface{}, error)
+
+	// TypeName returns the type name from the envelope without decoding the item.
+	TypeName(data []byte) (string, error)
+}
+
+var (
+	// ErrNilItem is returned when attempting to encode a nil item.
+	// It is the same error as reg.ErrNilItem.
+	ErrNilItem = reg.ErrNilItem
+
+	// ErrNoTypeName is returned when an envelope has no type name.
+	ErrNoTypeName = errors.New("no type name in envelope")
+)
+
+//////////////////////////////////////////////////////////////////////////
+
+func registryOrSingleton(registry reg.Registry) reg.Registry {
+	if registry == nil {
+		return reg.Singleton()
+	}
+	return registry
+}
+
+// isNil returns true if the item is nil or a nil pointer.
+func isNil(item interface{}) bool {
+	if item == nil {
+		return true
+	}
+	value := reflect.ValueOf(item)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
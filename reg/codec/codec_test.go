@@ -0,0 +1,181 @@
+package codec_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-type/reg"
+	"github.com/madkins23/go-type/reg/codec"
+	"github.com/madkins23/go-type/reg/codec/codectest"
+)
+
+func TestJSON(t *testing.T) {
+	codectest.Run(t, codec.NewJSON)
+
+	c := codec.NewJSON(codectest.NewRegistry(t))
+	data, err := c.Encode(&codectest.Alpha{Name: "Hubert", Number: 17.23})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"[codecTest]Alpha","data":{"Name":"Hubert","Number":17.23}}`, string(data))
+	_, err = c.TypeName([]byte(`{"data":{}}`))
+	assert.ErrorIs(t, err, codec.ErrNoTypeName)
+	_, err = c.Decode([]byte(`null`))
+	assert.ErrorIs(t, err, codec.ErrNoTypeName)
+}
+
+func TestXML(t *testing.T) {
+	codectest.Run(t, codec.NewXML)
+
+	c := codec.NewXML(codectest.NewRegistry(t))
+	data, err := c.Encode(&codectest.Alpha{Name: "Hubert", Number: 17.23})
+	require.NoError(t, err)
+	assert.Equal(t, `<item xmlns:xsi="`+reg.XMLSchemaInstance+`" xsi:type="[codecTest]Alpha">`+
+		`<Name>Hubert</Name><Number>17.23</Number></item>`, string(data))
+	_, err = c.TypeName([]byte(`<item><Name>Hubert</Name></item>`))
+	assert.ErrorIs(t, err, codec.ErrNoTypeName)
+	_, err = c.Decode([]byte(`<item/>`))
+	assert.ErrorIs(t, err, codec.ErrNoTypeName)
+	_, err = c.TypeName([]byte(``))
+	assert.Error(t, err)
+}
+
+func TestGob(t *testing.T) {
+	codectest.Run(t, codec.NewGob)
+
+	c := codec.NewGob(codectest.NewRegistry(t))
+	buffer := new(bytes.Buffer)
+	require.NoError(t, gob.NewEncoder(buffer).Encode(&struct {
+		Type string
+		Data []byte
+	}{Data: []byte{1}}))
+	_, err := c.TypeName(buffer.Bytes())
+	assert.ErrorIs(t, err, codec.ErrNoTypeName)
+	_, err = c.Decode([]byte("garbage"))
+	assert.Error(t, err)
+}
+
+func TestErrNilItem(t *testing.T) {
+	assert.Same(t, reg.ErrNilItem, codec.ErrNilItem)
+	registry := codectest.NewRegistry(t)
+	for _, c := range []codec.Codec{codec.NewJSON(registry), codec.NewXML(registry), codec.NewGob(registry)} {
+		_, err := c.Encode(nil)
+		assert.ErrorIs(t, err, reg.ErrNilItem)
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	examples := []interface{}{3.5, "text", []byte("bytes")}
+	sequences := []interface{}{[]int{1, 2}, map[string]int{"one": 1}}
+	for _, test := range []struct {
+		newCodec  func(reg.Registry) codec.Codec
+		supported []interface{}
+	}{
+		{codec.NewJSON, append(examples, sequences...)},
+		{codec.NewGob, append(examples, sequences...)},
+		{codec.NewXML, examples},
+	} {
+		// Predeclared and composite types are not registered.
+		_, err := test.newCodec(codectest.NewRegistry(t)).Encode(3.5)
+		assert.ErrorIs(t, err, &reg.ErrNotRegistered{})
+
+		c := test.newCodec(reg.NewRegistry(reg.WithDerivedNames()))
+		for _, example := range test.supported {
+			data, err := c.Encode(example)
+			require.NoError(t, err)
+			name, err := c.TypeName(data)
+			require.NoError(t, err)
+			assert.Equal(t, reflect.TypeOf(example).String(), name)
+			item, err := c.Decode(data)
+			require.NoError(t, err)
+			expected := reflect.New(reflect.TypeOf(example))
+			expected.Elem().Set(reflect.ValueOf(example))
+			assert.Equal(t, expected.Interface(), item)
+		}
+	}
+
+	// Slices and maps can't be written as single XML elements.
+	c := codec.NewXML(reg.NewRegistry(reg.WithDerivedNames()))
+	for _, example := range sequences {
+		_, err := c.Encode(example)
+		assert.Error(t, err)
+	}
+}
+
+func TestLenient(t *testing.T) {
+	full := codectest.NewRegistry(t)
+	require.NoError(t, full.Register(&codectest.Charlie{}))
+	for _, newCodec := range []func(reg.Registry) codec.Codec{codec.NewJSON, codec.NewXML, codec.NewGob} {
+		data, err := newCodec(full).Encode(&codectest.Charlie{Text: "unregistered"})
+		require.NoError(t, err)
+
+		partial := codectest.NewRegistry(t)
+		c := codec.NewLenient(newCodec(partial), partial)
+		item, err := c.Decode(data)
+		require.NoError(t, err)
+		unknown, ok := item.(*reg.Unknown)
+		require.True(t, ok)
+		assert.Equal(t, "[codecTest]Charlie", unknown.Name)
+		again, err := c.Encode(unknown)
+		require.NoError(t, err)
+		assert.Equal(t, data, again)
+		result, err := newCodec(full).Decode(again)
+		require.NoError(t, err)
+		assert.Equal(t, &codectest.Charlie{Text: "unregistered"}, result)
+
+		// Registered types are still handled normally.
+		data, err = c.Encode(&codectest.Alpha{Name: "Hubert"})
+		require.NoError(t, err)
+		item, err = c.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, &codectest.Alpha{Name: "Hubert"}, item)
+	}
+}
+
+// Failing is a registered type that fails to initialize.
+type Failing struct {
+	Text string
+}
+
+func (f *Failing) Init() error {
+	return errors.New("init failed")
+}
+
+// Counted is a registered type with a factory that counts calls.
+type Counted struct {
+	Text string
+}
+
+func TestLenientErrors(t *testing.T) {
+	for _, newCodec := range []func(reg.Registry) codec.Codec{codec.NewJSON, codec.NewXML, codec.NewGob} {
+		registry := codectest.NewRegistry(t)
+		require.NoError(t, registry.Register(&Failing{}))
+		calls := 0
+		require.NoError(t, registry.(reg.FactoryRegisterer).RegisterFactory(&Counted{}, func() interface{} {
+			calls++
+			return &Counted{}
+		}))
+		c := codec.NewLenient(newCodec(registry), registry)
+
+		// Registered types that fail to initialize are errors, not placeholders.
+		data, err := c.Encode(&Failing{Text: "failing"})
+		require.NoError(t, err)
+		item, err := c.Decode(data)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "init failed")
+		assert.Nil(t, item)
+
+		// The factory is called once per decoded item.
+		data, err = c.Encode(&Counted{Text: "counted"})
+		require.NoError(t, err)
+		calls = 0
+		item, err = c.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, &Counted{Text: "counted"}, item)
+		assert.Equal(t, 1, calls)
+	}
+}
@@ -0,0 +1,133 @@
+// Package codectest provides a conformance test for implementations of codec.Codec.
+//
+// Call Run from a test function with a function that creates a Codec for a Registry:
+//
+//	func TestConformance(t *testing.T) {
+//		codectest.Run(t, func(registry reg.Registry) codec.Codec {
+//			return NewMyCodec(registry)
+//		})
+//	}
+package codectest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/madkins23/go-type/reg"
+	"github.com/madkins23/go-type/reg/codec"
+)
+
+// Alias is the registry alias for the package containing the test types.
+const Alias = "codecTest"
+
+// Stuff is implemented by the test types.
+type Stuff interface {
+	Info() string
+}
+
+// Alpha is a test type.
+type Alpha struct {
+	Name   string
+	Number float32
+}
+
+// Info returns the name of the Alpha object.
+func (a *Alpha) Info() string {
+	return a.Name
+}
+
+// Bravo is a test type.
+type Bravo struct {
+	Finished   bool
+	Iterations int
+	Tags       []string
+}
+
+// Info returns a fixed string for Bravo objects.
+func (b *Bravo) Info() string {
+	return "bravo"
+}
+
+// Charlie is a test type that is never registered.
+type Charlie struct {
+	Text string
+}
+
+// NewRegistry returns a Registry with the test types registered.
+func NewRegistry(t *testing.T) reg.Registry {
+	registry := reg.NewRegistry()
+	require.NoError(t, registry.AddAlias(Alias, &Alpha{}))
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Bravo{}))
+	return registry
+}
+
+// Run the conformance test for the Codec created by the specified function.
+func Run(t *testing.T, newCodec func(registry reg.Registry) codec.Codec) {
+	t.Run("Cycle", func(t *testing.T) {
+		registry := NewRegistry(t)
+		c := newCodec(registry)
+		for _, item := range []Stuff{
+			&Alpha{Name: "Hubert", Number: 17.23},
+			&Alpha{},
+			&Bravo{Finished: true, Iterations: 79, Tags: []string{"one", "two"}},
+		} {
+			data, err := c.Encode(item)
+			require.NoError(t, err)
+			name, err := c.TypeName(data)
+			require.NoError(t, err)
+			expected, err := registry.NameFor(item)
+			require.NoError(t, err)
+			assert.Equal(t, expected, name)
+			result, err := c.Decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, item, result)
+		}
+	})
+
+	t.Run("AliasedName", func(t *testing.T) {
+		c := newCodec(NewRegistry(t))
+		data, err := c.Encode(&Bravo{})
+		require.NoError(t, err)
+		name, err := c.TypeName(data)
+		require.NoError(t, err)
+		assert.Equal(t, "["+Alias+"]Bravo", name)
+	})
+
+	t.Run("NilItem", func(t *testing.T) {
+		c := newCodec(NewRegistry(t))
+		_, err := c.Encode(nil)
+		assert.ErrorIs(t, err, codec.ErrNilItem)
+		_, err = c.Encode((*Alpha)(nil))
+		assert.ErrorIs(t, err, codec.ErrNilItem)
+	})
+
+	t.Run("EncodeUnregistered", func(t *testing.T) {
+		c := newCodec(NewRegistry(t))
+		_, err := c.Encode(&Charlie{Text: "unregistered"})
+		assert.Error(t, err)
+	})
+
+	t.Run("DecodeUnregistered", func(t *testing.T) {
+		registry := NewRegistry(t)
+		require.NoError(t, registry.Register(&Charlie{}))
+		data, err := newCodec(registry).Encode(&Charlie{Text: "unregistered"})
+		require.NoError(t, err)
+		c := newCodec(NewRegistry(t))
+		name, err := c.TypeName(data)
+		require.NoError(t, err)
+		assert.Equal(t, "["+Alias+"]Charlie", name)
+		_, err = c.Decode(data)
+		assert.Error(t, err)
+	})
+
+	t.Run("SeparateRegistries", func(t *testing.T) {
+		data, err := newCodec(NewRegistry(t)).Encode(&Alpha{Name: "Wilbur"})
+		require.NoError(t, err)
+		result, err := newCodec(NewRegistry(t)).Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, &Alpha{Name: "Wilbur"}, result)
+	})
+}
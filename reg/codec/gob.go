@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+// NewGob returns a Codec that serializes items into encoding/gob envelopes.
+// The envelope is a gob-encoded structure containing the type name
+// and the gob-encoded item.
+// Items are encoded directly rather than as interface values
+// so it is not necessary to register them with encoding/gob.
+// If the registry is nil the Singleton Registry will be used.
+func NewGob(registry reg.Registry) Codec {
+	return &gobCodec{
+		registry: registryOrSingleton(registry),
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+type gobCodec struct {
+	registry reg.Registry
+}
+
+type gobEnvelope struct {
+	Type string
+	Data []byte
+}
+
+// Encode the item into a gob envelope with its type name.
+func (c *gobCodec) Encode(item interface{}) ([]byte, error) {
+	if isNil(item) {
+		return nil, ErrNilItem
+	}
+
+	name, err := c.registry.NameFor(item)
+	if err != nil {
+		return nil, fmt.Errorf("get name for item: %w", err)
+	}
+
+	data := new(bytes.Buffer)
+	if err := gob.NewEncoder(data).Encode(item); err != nil {
+		return nil, fmt.Errorf("encode item %s: %w", name, err)
+	}
+
+	envelope := new(bytes.Buffer)
+	if err := gob.NewEncoder(envelope).Encode(&gobEnvelope{Type: name, Data: data.Bytes()}); err != nil {
+		return nil, fmt.Errorf("encode envelope: %w", err)
+	}
+
+	return envelope.Bytes(), nil
+}
+
+// Decode the gob envelope into a new instance of the named type.
+func (c *gobCodec) Decode(data []byte) (interface{}, error) {
+	envelope, err := c.envelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := c.registry.Make(envelope.Type)
+	if err != nil {
+		return nil, fmt.Errorf("make item: %w", err)
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(envelope.Data)).Decode(item); err != nil {
+		return nil, fmt.Errorf("decode item %s: %w", envelope.Type, err)
+	}
+
+	return item, nil
+}
+
+// TypeName returns the type name from the gob envelope without decoding the item.
+func (c *gobCodec) TypeName(data []byte) (string, error) {
+	envelope, err := c.envelope(data)
+	if err != nil {
+		return "", err
+	}
+
+	return envelope.Type, nil
+}
+
+func (c *gobCodec) envelope(data []byte) (*gobEnvelope, error) {
+	envelope := new(gobEnvelope)
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(envelope); err != nil {
+		return nil, fmt.Errorf("decode envelope: %w", err)
+	}
+	if envelope.Type == "" {
+		return nil, ErrNoTypeName
+	}
+
+	return envelope, nil
+}
@@ -0,0 +1,64 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+// NewJSON returns a Codec that serializes items into JSON envelopes:
+//
+//	{"type": "[typeUtils]Alpha", "data": {"Name": "Hubert", "Number": 17.23}}
+//
+// This is the same envelope used by reg.Poly.
+// If the registry is nil the Singleton Registry will be used.
+func NewJSON(registry reg.Registry) Codec {
+	return &jsonCodec{
+		registry: registryOrSingleton(registry),
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+type jsonCodec struct {
+	registry reg.Registry
+}
+
+// Encode the item into a JSON envelope with its type name.
+func (c *jsonCodec) Encode(item interface{}) ([]byte, error) {
+	if isNil(item) {
+		return nil, ErrNilItem
+	}
+
+	return json.Marshal(reg.NewPoly[interface{}](item, c.registry))
+}
+
+// Decode the JSON envelope into a new instance of the named type.
+func (c *jsonCodec) Decode(data []byte) (interface{}, error) {
+	if _, err := c.TypeName(data); err != nil {
+		return nil, err
+	}
+
+	poly := reg.NewPoly[interface{}](nil, c.registry)
+	if err := json.Unmarshal(data, poly); err != nil {
+		return nil, err
+	}
+
+	return poly.Item, nil
+}
+
+// TypeName returns the type name from the JSON envelope without decoding the item.
+func (c *jsonCodec) TypeName(data []byte) (string, error) {
+	envelope := new(struct {
+		Type string `json:"type"`
+	})
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return "", fmt.Errorf("unmarshal envelope: %w", err)
+	}
+	if envelope.Type == "" {
+		return "", ErrNoTypeName
+	}
+
+	return envelope.Type, nil
+}
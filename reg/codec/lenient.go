@@ -0,0 +1,72 @@
+package codec
+
+import (
+	"errors"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+// NewLenient returns a Codec that wraps the specified Codec.
+// Envelopes with type names that are not registered are decoded into
+// *reg.Unknown placeholders containing the type name and the entire envelope
+// instead of returning an error.
+// Encoding a *reg.Unknown returns its raw envelope unchanged,
+// so a placeholder must be re-encoded with the same kind of Codec that decoded it.
+// If the registry is nil the Singleton Registry will be used.
+func NewLenient(codec Codec, registry reg.Registry) Codec {
+	return &lenientCodec{
+		Codec:    codec,
+		registry: registryOrSingleton(registry),
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+type lenientCodec struct {
+	Codec
+	registry reg.Registry
+}
+
+// Encode the item into an envelope with its type name.
+// A *reg.Unknown item is encoded as its raw envelope.
+func (c *lenientCodec) Encode(item interface{}) ([]byte, error) {
+	if unknown, ok := item.(*reg.Unknown); ok && unknown != nil {
+		return append(make([]byte, 0, len(unknown.Raw)), unknown.Raw...), nil
+	}
+	return c.Codec.Encode(item)
+}
+
+// Decode the envelope into a new instance of the named type.
+// If the type name is not registered a *reg.Unknown placeholder is returned.
+// Other errors, such as a failure to initialize a new instance, are returned as usual.
+func (c *lenientCodec) Decode(data []byte) (interface{}, error) {
+	name, err := c.Codec.TypeName(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if finder, ok := c.registry.(reg.TypeFinder); ok {
+		// Check the name without creating an instance that would be discarded.
+		if _, err := finder.TypeFor(name); err != nil {
+			return unknownOrError(name, data, err)
+		}
+	}
+
+	item, err := c.Codec.Decode(data)
+	if err != nil {
+		return unknownOrError(name, data, err)
+	}
+
+	return item, nil
+}
+
+// unknownOrError returns a *reg.Unknown placeholder if the error is due to an unregistered name,
+// otherwise the error.
+func unknownOrError(name string, data []byte, err error) (interface{}, error) {
+	var notRegistered *reg.ErrNotRegistered
+	if errors.As(err, &notRegistered) {
+		return reg.NewUnknown(name, data), nil
+	}
+
+	return nil, err
+}
@@ -0,0 +1,90 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+// XMLElement is the name of the XML element for envelopes created by the XML Codec.
+const XMLElement = "item"
+
+// NewXML returns a Codec that serializes items into XML envelopes
+// with the type name in an xsi:type attribute:
+//
+//	<item xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="[typeUtils]Alpha">
+//	  <Name>Hubert</Name>
+//	  <Number>17.23</Number>
+//	</item>
+//
+// This is the same envelope used by reg.Poly.
+// If the registry is nil the Singleton Registry will be used.
+func NewXML(registry reg.Registry) Codec {
+	return &xmlCodec{
+		registry: registryOrSingleton(registry),
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+type xmlCodec struct {
+	registry reg.Registry
+}
+
+// Encode the item into an XML envelope with its type name.
+func (c *xmlCodec) Encode(item interface{}) ([]byte, error) {
+	if isNil(item) {
+		return nil, ErrNilItem
+	}
+
+	buffer := new(bytes.Buffer)
+	encoder := xml.NewEncoder(buffer)
+	start := xml.StartElement{Name: xml.Name{Local: XMLElement}}
+	if err := encoder.EncodeElement(reg.NewPoly[interface{}](item, c.registry), start); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, fmt.Errorf("flush encoder: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Decode the XML envelope into a new instance of the named type.
+func (c *xmlCodec) Decode(data []byte) (interface{}, error) {
+	if _, err := c.TypeName(data); err != nil {
+		return nil, err
+	}
+
+	poly := reg.NewPoly[interface{}](nil, c.registry)
+	if err := xml.Unmarshal(data, poly); err != nil {
+		return nil, err
+	}
+
+	return poly.Item, nil
+}
+
+// TypeName returns the type name from the XML envelope without decoding the item.
+func (c *xmlCodec) TypeName(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("no envelope element")
+		} else if err != nil {
+			return "", fmt.Errorf("read envelope: %w", err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "type" && (attr.Name.Space == reg.XMLSchemaInstance || attr.Name.Space == "xsi") {
+					return attr.Value, nil
+				}
+			}
+			return "", ErrNoTypeName
+		}
+	}
+}
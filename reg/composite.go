@@ -0,0 +1,250 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Composite and predeclared types are not registered.
+// Their names are derived from the names of their element types:
+//
+//	[]<elem>            slice
+//	[<len>]<elem>       array
+//	map[<key>]<elem>    map
+//	*<elem>             pointer
+//
+// where the element and key names may be registered, composite, or predeclared names.
+// Predeclared types use their Go names (e.g. int, string, float64)
+// and the empty interface is named "interface {}" as in reflect.Type.String.
+// Array types with names longer than maxArrayLength elements or maxArraySize bytes
+// are not created from names.
+// Nor are types with names longer than maxNameLength bytes
+// or nested more than maxNestingDepth levels.
+//
+// Examples: []int, map[string][app]Alpha, []*[app]Bravo.
+//
+// Make and TypeFor accept derived names but NameFor only returns them
+// for a Registry created with the WithDerivedNames option.
+
+// predeclaredTypes maps the names of predeclared types to their types.
+var predeclaredTypes = make(map[string]reflect.Type)
+
+const (
+	// maxArrayLength is the largest array length accepted in type names.
+	maxArrayLength = 1 << 16
+
+	// maxArraySize is the largest size in bytes of an array type accepted in type names.
+	// Type names may come from untrusted input so Make must not allocate arbitrarily large arrays.
+	maxArraySize = 1 << 20
+
+	// maxNameLength is the longest name accepted for composite and generic types.
+	maxNameLength = 1 << 12
+
+	// maxNestingDepth is the deepest nesting of element and type argument names
+	// accepted for composite and generic types.
+	// The reflect package keeps every type created for the life of the process.
+	maxNestingDepth = 32
+)
+
+func init() {
+	for _, example := range []interface{}{
+		false, "",
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0), uintptr(0),
+		float32(0), float64(0), complex64(0), complex128(0),
+	} {
+		exType := reflect.TypeOf(example)
+		predeclaredTypes[exType.Name()] = exType
+	}
+	emptyInterface := reflect.TypeOf((*interface{})(nil)).Elem()
+	predeclaredTypes[emptyInterface.String()] = emptyInterface
+}
+
+// WithDerivedNames configures a Registry to return names from NameFor
+// for unregistered composite and predeclared types,
+// for example []int or map[string][app]Alpha.
+// By default NameFor returns an error for these types.
+//
+// Serializers that use NameFor (e.g. Poly and the codec and json packages)
+// will then write these values with their type names instead of treating them as unregistered.
+// Like registered types they are created via Make when read back,
+// so an int stored in an interface{} is read back as a *int.
+func WithDerivedNames() Option {
+	return func(reg *registry) {
+		reg.derivedNames = true
+	}
+}
+
+// derivedName returns the name of an unregistered composite or predeclared type.
+func (reg *registry) derivedName(itemType reflect.Type) (string, error) {
+	if itemType.PkgPath() == "" {
+		if predeclared, found := predeclaredTypes[itemType.String()]; found && predeclared == itemType {
+			return itemType.String(), nil
+		}
+	}
+
+	// Named composite types (e.g. type List []Alpha) must be registered.
+	if itemType.Name() == "" {
+		switch itemType.Kind() {
+		case reflect.Ptr:
+			elem, err := reg.elementName(itemType.Elem())
+			return "*" + elem, err
+		case reflect.Slice:
+			elem, err := reg.elementName(itemType.Elem())
+			return "[]" + elem, err
+		case reflect.Array:
+			elem, err := reg.elementName(itemType.Elem())
+			return "[" + strconv.Itoa(itemType.Len()) + "]" + elem, err
+		case reflect.Map:
+			key, err := reg.elementName(itemType.Key())
+			if err != nil {
+				return "", err
+			}
+			elem, err := reg.elementName(itemType.Elem())
+			return "map[" + key + "]" + elem, err
+		}
+	}
+
+	return "", &ErrNotRegistered{Type: itemType}
+}
+
+// elementName returns the registered or derived name of the element type of a composite type.
+func (reg *registry) elementName(elemType reflect.Type) (string, error) {
+	if item, found := reg.byType[elemType]; found {
+		return item.currentName, nil
+	}
+
+	return reg.derivedName(elemType)
+}
+
+// derivedType returns the type for the name of a composite, predeclared,
+// or instantiated generic type.
+func (reg *registry) derivedType(name string) (reflect.Type, error) {
+	return reg.nestedType(name, 0)
+}
+
+// nestedType returns the type for the name of a composite, predeclared,
+// or instantiated generic type nested at the specified depth within another type name.
+func (reg *registry) nestedType(name string, depth int) (reflect.Type, error) {
+	if len(name) > maxNameLength {
+		return nil, &ErrNotRegistered{Name: name, Err: fmt.Errorf("name is longer than %d bytes", maxNameLength)}
+	}
+
+	itemType, err := parseComposite(name, depth, reg.elementType)
+	if itemType == nil && err == nil {
+		itemType, err = reg.genericType(name, depth)
+	}
+
+	if err != nil || itemType == nil {
+		return nil, &ErrNotRegistered{Name: name, Err: err}
+	}
+
+	return itemType, nil
+}
+
+// isDerivedName returns true if the name has the form of a composite or predeclared type name.
+// These names are reserved and can't be used as explicit names.
+func isDerivedName(name string) bool {
+	itemType, err := parseComposite(name, 0, func(string, int) (reflect.Type, error) {
+		return predeclaredTypes["interface {}"], nil
+	})
+	return itemType != nil || err != nil
+}
+
+// parseComposite returns the type for the name of a composite or predeclared type.
+// Element and key types are found by the element function at the next depth.
+// Returns nil and no error if the name is not the name of a composite or predeclared type.
+func parseComposite(name string, depth int, element func(name string, depth int) (reflect.Type, error)) (reflect.Type, error) {
+	if predeclared, found := predeclaredTypes[name]; found {
+		return predeclared, nil
+	}
+	if depth >= maxNestingDepth {
+		return nil, fmt.Errorf("name is nested more than %d levels", maxNestingDepth)
+	}
+
+	switch {
+	case strings.HasPrefix(name, "*"):
+		elemType, err := element(name[1:], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PtrTo(elemType), nil
+	case strings.HasPrefix(name, "[]"):
+		elemType, err := element(name[2:], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elemType), nil
+	case strings.HasPrefix(name, "map["):
+		end := closingBracket(name, len("map["))
+		if end < 0 {
+			return nil, nil
+		}
+		keyType, err := element(name[len("map["):end], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if !keyType.Comparable() {
+			return nil, fmt.Errorf("map key type %v is not comparable", keyType)
+		}
+		elemType, err := element(name[end+1:], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(keyType, elemType), nil
+	case strings.HasPrefix(name, "["):
+		end := strings.Index(name, "]")
+		if end < 0 {
+			return nil, nil
+		}
+		length, err := strconv.Atoi(name[1:end])
+		if err != nil || length < 0 {
+			// Not an array, possibly an aliased name.
+			return nil, nil
+		}
+		if length > maxArrayLength {
+			return nil, fmt.Errorf("array length %d exceeds %d", length, maxArrayLength)
+		}
+		elemType, err := element(name[end+1:], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if size := elemType.Size(); size > 0 && uintptr(length) > maxArraySize/size {
+			return nil, fmt.Errorf("array size exceeds %d bytes", maxArraySize)
+		}
+		return reflect.ArrayOf(length, elemType), nil
+	}
+
+	return nil, nil
+}
+
+// elementType returns the registered or derived type for the name of the element type of a composite type
+// nested at the specified depth.
+func (reg *registry) elementType(name string, depth int) (reflect.Type, error) {
+	if item, found := reg.byName[name]; found {
+		return item.typeObj, nil
+	}
+
+	return reg.nestedType(name, depth)
+}
+
+// closingBracket returns the index of the bracket that closes the bracket just before start
+// or -1 if there is none. Nested brackets (e.g. in aliased names) are skipped.
+func closingBracket(name string, start int) int {
+	depth := 0
+	for i := start; i < len(name); i++ {
+		switch name[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,176 @@
+package reg
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type AlphaList []Alpha
+
+func TestCompositeNames(t *testing.T) {
+	registry := NewRegistry(WithDerivedNames())
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Bravo{}))
+	for name, example := range map[string]interface{}{
+		"int":                                  17,
+		"string":                               "",
+		"interface {}":                         new(interface{}),
+		"[]int":                                []int{},
+		"[]*[app]Alpha":                        []*Alpha{},
+		"[3][app]Bravo":                        [3]Bravo{},
+		"map[string][app]Bravo":                map[string]Bravo{},
+		"map[[app]Alpha][][app]Bravo":          map[Alpha][]Bravo{},
+		"map[string]interface {}":              map[string]interface{}{},
+		"*[app]Alpha":                          new(*Alpha),
+		"map[[app]Bravo]map[int][]*[app]Alpha": map[Bravo]map[int][]*Alpha{},
+	} {
+		actual, err := registry.NameFor(example)
+		require.NoError(t, err, name)
+		assert.Equal(t, name, actual)
+		item, err := registry.Make(name)
+		require.NoError(t, err, name)
+		exType := reflect.TypeOf(example)
+		if exType.Kind() != reflect.Ptr {
+			exType = reflect.PtrTo(exType)
+		}
+		assert.Equal(t, exType, reflect.TypeOf(item), name)
+		itemType, err := registry.(TypeFinder).TypeFor(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, exType.Elem(), itemType, name)
+	}
+
+	// By default derived names are only accepted by Make and TypeFor.
+	registry = NewRegistry()
+	require.NoError(t, registry.Register(&Alpha{}))
+	for _, example := range []interface{}{17, "", []int{}, []*Alpha{}, map[string]Alpha{}} {
+		_, err := registry.NameFor(example)
+		assert.ErrorIs(t, err, &ErrNotRegistered{Type: reflect.TypeOf(example)})
+	}
+	item, err := registry.Make("[]" + packageName + "/Alpha")
+	require.NoError(t, err)
+	assert.IsType(t, &[]Alpha{}, item)
+}
+
+func TestCompositeNameErrors(t *testing.T) {
+	registry := NewRegistry(WithDerivedNames())
+	require.NoError(t, registry.Register(&Alpha{}))
+	for _, example := range []interface{}{
+		[]Charlie{},
+		map[string]*Charlie{},
+		AlphaList{},
+		make(chan int),
+	} {
+		_, err := registry.NameFor(example)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no registration for type")
+	}
+	for _, name := range []string{
+		"[]" + packageName + "/Charlie",
+		"map[string" + packageName + "/Alpha",
+		"map[[]int]int",
+		"[x]int",
+		"[-1]int",
+		"[3",
+		"*",
+		"chan int",
+	} {
+		_, err := registry.Make(name)
+		assert.Error(t, err, name)
+		assert.Contains(t, err.Error(), "no registration for type named '"+name+"'")
+	}
+	_, err := registry.Make("map[[]int]int")
+	assert.Contains(t, err.Error(), "map key type []int is not comparable")
+}
+
+func TestCompositeArrayLimits(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&Alpha{}))
+	_, err := registry.Make("[65536]uint8")
+	require.NoError(t, err)
+	for _, name := range []string{
+		"[100000000000]int",
+		"[9223372036854775807]int",
+		"[65537]bool",
+		"[1024][1024][1024]int",
+		"[1000000]" + packageName + "/Alpha",
+	} {
+		_, err := registry.Make(name)
+		assert.ErrorIs(t, err, &ErrNotRegistered{Name: name}, name)
+		_, err = registry.(TypeFinder).TypeFor(name)
+		assert.ErrorIs(t, err, &ErrNotRegistered{Name: name}, name)
+	}
+
+	// Hostile names in serialized data are rejected the same way.
+	poly := Poly[interface{}]{Registry: registry}
+	err = json.Unmarshal([]byte(`{"type":"[9223372036854775807]int","data":[]}`), &poly)
+	assert.ErrorIs(t, err, &ErrNotRegistered{})
+	err = json.Unmarshal([]byte(`{"type":"[100000000000]int","data":[]}`), &poly)
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: "[100000000000]int"})
+	assert.Nil(t, poly.Item)
+}
+
+func TestCompositeNestingLimits(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	require.NoError(t, registry.Register(&Box[int]{}))
+	deepest := strings.Repeat("*", maxNestingDepth) + "int"
+	item, err := registry.Make(deepest)
+	require.NoError(t, err)
+	assert.Equal(t, deepest, reflect.TypeOf(item).Elem().String())
+
+	for _, name := range []string{
+		strings.Repeat("*", maxNestingDepth+1) + "int",
+		strings.Repeat("[]", maxNestingDepth+1) + "int",
+		strings.Repeat("map[string]", maxNestingDepth+1) + "int",
+		strings.Repeat("[1]", maxNestingDepth+1) + "int",
+		strings.Repeat("map[", maxNestingDepth+1) + "int" + strings.Repeat("]int", maxNestingDepth+1),
+		strings.Repeat("[app]Box[", maxNestingDepth+1) + "int" + strings.Repeat("]", maxNestingDepth+1),
+		strings.Repeat("*", 100000) + "int",
+		"[app]Box[" + strings.Repeat("int,", 100000) + "int]",
+	} {
+		_, err := registry.Make(name)
+		assert.ErrorIs(t, err, &ErrNotRegistered{Name: name})
+		_, err = registry.(TypeFinder).TypeFor(name)
+		assert.ErrorIs(t, err, &ErrNotRegistered{Name: name})
+	}
+}
+
+// Hotel provides a name reserved for a composite type.
+type Hotel struct{}
+
+func (h Hotel) RegistryName() string {
+	return "[]" + packageName + "/Alpha"
+}
+
+func TestCompositeNamesReserved(t *testing.T) {
+	registry := NewRegistry(WithDerivedNames())
+	require.NoError(t, registry.Register(&Alpha{}))
+	for _, name := range []string{"int", "interface {}", "[]int", "*x", "map[string]int", "[3]y", "[100000000]int"} {
+		err := registry.(ExplicitRegisterer).RegisterAs(name, &Bravo{})
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "is reserved", name)
+		err = registry.(PrototypeRegisterer).RegisterPrototype(name, &Alpha{})
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "is reserved", name)
+	}
+	err := registry.Register(&Hotel{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is reserved")
+
+	// Derived names still refer to derived types.
+	name, err := registry.NameFor(5)
+	require.NoError(t, err)
+	assert.Equal(t, "int", name)
+	item, err := registry.Make("int")
+	require.NoError(t, err)
+	assert.IsType(t, new(int), item)
+
+	// Aliased and other bracketed names are not reserved.
+	require.NoError(t, registry.(ExplicitRegisterer).RegisterAs("[app]Bravo", &Bravo{}))
+}
@@ -0,0 +1,149 @@
+package reg
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// deepCopy returns a deep copy of the specified value.
+// Pointers, slices, maps, and interfaces are copied recursively.
+// Shared pointers in the original are shared in the copy as well,
+// which also prevents infinite recursion on cyclic data.
+// Unexported struct fields are copied but not recursively,
+// so reference types in unexported fields are shared with the original
+// (see sharedField).
+func deepCopy(value reflect.Value) reflect.Value {
+	return copyValue(value, make(map[uintptr]reflect.Value))
+}
+
+func copyValue(value reflect.Value, copied map[uintptr]reflect.Value) reflect.Value {
+	if !value.IsValid() {
+		return value
+	}
+
+	valueType := value.Type()
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return reflect.Zero(valueType)
+		}
+		if previous, found := copied[value.Pointer()]; found && previous.Type() == valueType {
+			return previous
+		}
+		result := reflect.New(valueType.Elem())
+		copied[value.Pointer()] = result
+		result.Elem().Set(copyValue(value.Elem(), copied))
+		return result
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return reflect.Zero(valueType)
+		}
+		result := reflect.New(valueType).Elem()
+		result.Set(copyValue(value.Elem(), copied))
+		return result
+
+	case reflect.Struct:
+		result := reflect.New(valueType).Elem()
+		result.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if valueType.Field(i).IsExported() {
+				result.Field(i).Set(copyValue(value.Field(i), copied))
+			}
+		}
+		return result
+
+	case reflect.Slice:
+		if value.IsNil() {
+			return reflect.Zero(valueType)
+		}
+		result := reflect.MakeSlice(valueType, value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(copyValue(value.Index(i), copied))
+		}
+		return result
+
+	case reflect.Array:
+		result := reflect.New(valueType).Elem()
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(copyValue(value.Index(i), copied))
+		}
+		return result
+
+	case reflect.Map:
+		if value.IsNil() {
+			return reflect.Zero(valueType)
+		}
+		result := reflect.MakeMapWithSize(valueType, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(copyValue(iter.Key(), copied), copyValue(iter.Value(), copied))
+		}
+		return result
+
+	default:
+		// Numbers, strings, channels, functions, etc.
+		return value
+	}
+}
+
+// sharedField returns the path of an unexported field of the value that contains
+// a non-nil map, slice, or pointer or an empty string if there is none.
+// These would be shared by all copies of the value made by deepCopy.
+func sharedField(value reflect.Value) string {
+	return findShared(value, value.Type().String(), false, make(map[uintptr]bool))
+}
+
+func findShared(value reflect.Value, path string, unexported bool, visited map[uintptr]bool) string {
+	kind := value.Kind()
+	if kind == reflect.Ptr || kind == reflect.Map || kind == reflect.Slice {
+		if value.IsNil() {
+			return ""
+		} else if unexported {
+			return path
+		}
+	}
+
+	switch kind {
+	case reflect.Ptr:
+		if visited[value.Pointer()] {
+			return ""
+		}
+		visited[value.Pointer()] = true
+		return findShared(value.Elem(), path, false, visited)
+
+	case reflect.Interface:
+		if !value.IsNil() {
+			return findShared(value.Elem(), path, unexported, visited)
+		}
+
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			field := valueType.Field(i)
+			if shared := findShared(value.Field(i), path+"."+field.Name, unexported || !field.IsExported(), visited); shared != "" {
+				return shared
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if shared := findShared(value.Index(i), path+"["+strconv.Itoa(i)+"]", unexported, visited); shared != "" {
+				return shared
+			}
+		}
+
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if shared := findShared(iter.Key(), path+"[]", false, visited); shared != "" {
+				return shared
+			}
+			if shared := findShared(iter.Value(), path+"[]", false, visited); shared != "" {
+				return shared
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+package reg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type copyNode struct {
+	Name     string
+	Children []*copyNode
+	Shared   *Alpha
+	Extra    interface{}
+	Lookup   map[string][]int
+	Fixed    [2]*Alpha
+	private  *Alpha
+}
+
+func TestDeepCopy(t *testing.T) {
+	shared := &Alpha{Name: "shared"}
+	private := &Alpha{Name: "private"}
+	original := &copyNode{
+		Name:     "root",
+		Children: []*copyNode{{Name: "child", Shared: shared}},
+		Shared:   shared,
+		Extra:    &Bravo{Iterations: 3},
+		Lookup:   map[string][]int{"one": {1}},
+		Fixed:    [2]*Alpha{shared, nil},
+		private:  private,
+	}
+	original.Children[0].Children = []*copyNode{original}
+
+	copied := deepCopy(reflect.ValueOf(original)).Interface().(*copyNode)
+	assert.NotSame(t, original, copied)
+	assert.Equal(t, "root", copied.Name)
+	assert.NotSame(t, original.Children[0], copied.Children[0])
+	assert.NotSame(t, shared, copied.Shared)
+	assert.Equal(t, *shared, *copied.Shared)
+	assert.NotSame(t, original.Extra, copied.Extra)
+	assert.Equal(t, &Bravo{Iterations: 3}, copied.Extra)
+
+	// Shared pointers remain shared and cycles are preserved.
+	assert.Same(t, copied.Shared, copied.Children[0].Shared)
+	assert.Same(t, copied.Shared, copied.Fixed[0])
+	assert.Same(t, copied, copied.Children[0].Children[0])
+
+	// Unexported fields are copied shallowly.
+	assert.Same(t, private, copied.private)
+
+	copied.Lookup["one"][0] = 2
+	copied.Lookup["two"] = []int{2}
+	assert.Equal(t, map[string][]int{"one": {1}}, original.Lookup)
+}
+
+func TestSharedField(t *testing.T) {
+	node := copyNode{Name: "root", Lookup: map[string][]int{"one": {1}}, Extra: &Alpha{}}
+	assert.Equal(t, "", sharedField(reflect.ValueOf(node)))
+	node.Children = []*copyNode{{Name: "child", private: &Alpha{}}}
+	assert.Equal(t, "reg.copyNode.Children[0].private", sharedField(reflect.ValueOf(node)))
+	node.Children[0].private = nil
+	node.Children[0].Children = []*copyNode{&node}
+	assert.Equal(t, "", sharedField(reflect.ValueOf(node)))
+	node = copyNode{Extra: copyNode{private: &Alpha{}}}
+	assert.Equal(t, "reg.copyNode.Extra.private", sharedField(reflect.ValueOf(node)))
+}
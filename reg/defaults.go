@@ -0,0 +1,140 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// DefaultTag is the struct tag containing default field values (see WithDefaults).
+const DefaultTag = "default"
+
+// WithDefaults configures a Registry to fill new instances created by Make
+// with default values from struct tags:
+//
+//	type Config struct {
+//		Name    string        `default:"server"`
+//		Port    int           `default:"8080"`
+//		Verbose bool          `default:"true"`
+//		Timeout time.Duration `default:"30s"`
+//		Limits  Limits
+//	}
+//
+// Numbers, strings, booleans, and time.Duration values are supported.
+// Nested struct fields (not pointers to structs) are filled from their own default tags.
+// Default tags are parsed when a type is registered and any errors are returned then.
+// Defaults are not applied to instances created by factory functions.
+func WithDefaults() Option {
+	return func(reg *registry) {
+		reg.defaults = true
+	}
+}
+
+// fieldDefault is the default value for a field in a registered type.
+type fieldDefault struct {
+	// index is the sequence of field indexes to reach the field (see reflect.Value.FieldByIndex).
+	index []int
+
+	// value to be set into the field.
+	value reflect.Value
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseDefaults returns the default values for the fields of the specified type.
+func parseDefaults(itemType reflect.Type) ([]fieldDefault, error) {
+	if itemType.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	return collectDefaults(itemType, "", nil, nil)
+}
+
+func collectDefaults(structType reflect.Type, path string, index []int, defaults []fieldDefault) ([]fieldDefault, error) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldPath := path + field.Name
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		tag, tagged := field.Tag.Lookup(DefaultTag)
+		if !tagged {
+			if field.Type.Kind() == reflect.Struct {
+				nested, err := collectDefaults(field.Type, fieldPath+".", fieldIndex, nil)
+				if err != nil {
+					return nil, err
+				}
+				if len(nested) > 0 && !field.IsExported() && !field.Anonymous {
+					return nil, fmt.Errorf("defaults in unexported field %s", fieldPath)
+				}
+				defaults = append(defaults, nested...)
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			return nil, fmt.Errorf("default for unexported field %s", fieldPath)
+		}
+
+		value, err := parseDefault(field.Type, tag)
+		if err != nil {
+			return nil, fmt.Errorf("bad default for field %s: %w", fieldPath, err)
+		}
+
+		defaults = append(defaults, fieldDefault{index: fieldIndex, value: value})
+	}
+
+	return defaults, nil
+}
+
+// parseDefault converts the default tag string into a value of the specified type.
+func parseDefault(fieldType reflect.Type, tag string) (reflect.Value, error) {
+	value := reflect.New(fieldType).Elem()
+	if fieldType == durationType {
+		duration, err := time.ParseDuration(tag)
+		if err != nil {
+			return value, err
+		}
+		value.SetInt(int64(duration))
+		return value, nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		value.SetString(tag)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(tag)
+		if err != nil {
+			return value, err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(tag, 0, fieldType.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(tag, 0, fieldType.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(tag, fieldType.Bits())
+		if err != nil {
+			return value, err
+		}
+		value.SetFloat(f)
+	default:
+		return value, fmt.Errorf("unsupported type %v", fieldType)
+	}
+
+	return value, nil
+}
+
+// applyDefaults sets the default values into the fields of the struct value.
+func applyDefaults(value reflect.Value, defaults []fieldDefault) {
+	for _, fd := range defaults {
+		value.FieldByIndex(fd.index).Set(fd.value)
+	}
+}
@@ -0,0 +1,108 @@
+package reg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Limits struct {
+	Max   uint16  `default:"0x100"`
+	Ratio float64 `default:"0.75"`
+}
+
+type Config struct {
+	Name    string        `default:"server"`
+	Port    int           `default:"8080"`
+	Verbose bool          `default:"true"`
+	Timeout time.Duration `default:"1m30s"`
+	Limits  Limits
+	Other   *Limits
+	Plain   string
+}
+
+type BadDefault struct {
+	Port int `default:"eighty"`
+}
+
+type BadNested struct {
+	Limits struct {
+		Verbose bool `default:"maybe"`
+	}
+}
+
+type UnsupportedDefault struct {
+	Tags []string `default:"one,two"`
+}
+
+type PrivateDefault struct {
+	name string `default:"private"`
+}
+
+func TestDefaults(t *testing.T) {
+	for _, registry := range []Registry{NewRegistry(WithDefaults()), NewRegistrar(WithDefaults())} {
+		require.NoError(t, registry.Register(&Config{}))
+		item, err := MakeAs[*Config](registry, packageName+"/Config")
+		require.NoError(t, err)
+		assert.Equal(t, &Config{
+			Name:    "server",
+			Port:    8080,
+			Verbose: true,
+			Timeout: 90 * time.Second,
+			Limits:  Limits{Max: 256, Ratio: 0.75},
+		}, item)
+
+		// Each instance is separate.
+		item.Limits.Max = 1
+		item, err = MakeAs[*Config](registry, packageName+"/Config")
+		require.NoError(t, err)
+		assert.Equal(t, uint16(256), item.Limits.Max)
+	}
+}
+
+func TestDefaultsOff(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&Config{}))
+	require.NoError(t, registry.Register(&BadDefault{}))
+	item, err := registry.Make(packageName + "/Config")
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, item)
+}
+
+func TestDefaultsErrors(t *testing.T) {
+	registry := NewRegistry(WithDefaults())
+	err := registry.Register(&BadDefault{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad default for field Port")
+	err = registry.Register(&BadNested{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad default for field Limits.Verbose")
+	err = registry.Register(&UnsupportedDefault{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported type []string")
+	err = registry.Register(&PrivateDefault{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "default for unexported field name")
+	assert.Empty(t, registry.(NameLister).Names())
+}
+
+func TestDefaultsWithInitializer(t *testing.T) {
+	registry := NewRegistry(WithDefaults())
+	require.NoError(t, registry.Register(&Echo{}))
+	item, err := registry.Make(packageName + "/Echo")
+	require.NoError(t, err)
+	assert.Equal(t, &Echo{Count: 3, Values: []int{0, 0, 0}}, item)
+}
+
+// Echo uses a default value during initialization.
+type Echo struct {
+	Count  int `default:"3"`
+	Values []int
+}
+
+func (e *Echo) Init() error {
+	e.Values = make([]int, e.Count)
+	return nil
+}
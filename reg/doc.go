@@ -21,22 +21,84 @@
 // The registry can then be used to look up object type by name,
 // create new instance of type by name, and look up type name from an instance.
 //
+// By default new instances are created with all fields set to zero values.
+// Types that require initialization (e.g. maps or channels) may be registered
+// with a factory function via reg.FactoryRegisterer.RegisterFactory.
+// Alternatively, types that implement reg.Initializer will have their Init method
+// called on each new zero value instance.
+// A Registry created with the reg.WithDefaults option will fill
+// new instances with values from `default:"..."` struct tags.
+//
+// A prototype object may be registered via reg.PrototypeRegisterer.RegisterPrototype.
+// New instances are then created as deep copies of the prototype.
+// Unexported fields can't be copied deeply, so prototypes with non-nil maps, slices,
+// or pointers in unexported fields are rejected.
+// Prototypes may also be registered with preset names (e.g. "small-cache")
+// so that different configurations of the same type can be created by name.
+//
+// Registry objects provided by this package also implement the optional
+// reg.TypeFinder and reg.NameLister interfaces to look up types by name
+// without creating instances and to list registered names,
+// as well as reg.ExplicitRegisterer, reg.FactoryRegisterer, reg.PrototypeRegisterer,
+// and reg.AliasRemover.
+// Other implementations of reg.Registry need not implement them.
+// Wrappers and top-level functions return a reg.ErrUnsupported error
+// for methods that the wrapped Registry doesn't implement.
+//
 // # Type Naming
 //
 // Full type names are acquired from the Go Type object.
 // The full name is a combination of the package path
 // (e.g. github.com/madkins23/go-type/reg) and type name.
+// Types declared in package main have names like main/Config.
+// Use the reg.WithMainPath option to replace main with a more specific path.
+//
+// Composite types (slices, arrays, maps, and pointers) and predeclared types (e.g. int or string)
+// are not registered.
+// Their names are derived from the names of their element types,
+// for example []int, map[string][app]Alpha, or []*[app]Bravo.
+// Make can create instances of these types from their names.
+// NameFor only returns these names for a Registry created with the reg.WithDerivedNames option
+// so that values of these types are not serialized with type names by default.
+//
+// Instantiations of generic types are registered individually.
+// Their names contain the registry names of their type arguments,
+// for example [app]Box[[app]Alpha] or [app]Pair[string,[]int].
+// Type arguments must be registered (or composite or predeclared) before the instantiation.
 //
 // Registered types may have multiple names.
 // The first one is the full type name, the rest are names created using aliases.
-// When finding a name for a registered type via NameFor() the  shortest name will be returned.
+// When finding a name for a registered type via NameFor() the  shortest name will be returned,
+// with names of the same length ordered lexically so the result is always the same.
+// A different policy for choosing names may be specified with the reg.WithNamePolicy option,
+// for example reg.AliasPriority or reg.PreferNames.
 // When finding the type from the name all names will be checked.
 //
+// Generated names depend on package paths, so renaming or moving a package
+// changes the names of its types.
+// Use reg.ExplicitRegisterer.RegisterAs to register a type with an explicit name
+// which will then be its current name.
+// Alternatively, a type may provide its own name by implementing reg.Namer,
+// keeping the name next to the type definition.
+// The generated full and aliased names are still registered for lookup.
+// A name may not be used by more than one registered type.
+// Names of composite and predeclared types (e.g. int or []string)
+// are reserved and can't be used as explicit names.
+//
+// Unexported types are normally rejected by Register.
+// They may be registered with explicit names via reg.ExplicitRegisterer.RegisterAs
+// or by any means in a Registry created with the reg.WithPrivateTypes option.
+//
 // # Aliases
 //
 // Aliases may be defined for packages in order to reduce type name size
 // when requesting a name via NameFor().
 // An alias is specified with a string and an example instance from the package.
+// An alias also applies to subpackages of its package,
+// for example [app]sub/pkg/Type for a type in the sub/pkg subpackage.
+// Aliases may be added before or after registering the types in a package.
+// Use reg.AliasRemover.RemoveAlias or reg.AliasRemover.ReplaceAlias to change aliases later.
+// Aliased names may optionally be kept for lookup while migrating stored data.
 //
 // After a reg.Registry is created use reg.Registry.Alias() to specify
 // a name for the package, where the package is specified by a pointer
@@ -47,6 +109,52 @@
 // Since these can be long it is possible to provide an alias to a package
 // which will be used in serialized objects.
 //
+// # Generic Helpers
+//
+// The generic functions reg.MakeAs and reg.NameOf work with any Registry
+// and avoid type assertions after reg.Registry.Make and the need to
+// construct an example object for reg.Registry.NameFor.
+//
+// # Serialization
+//
+// The reg.Poly generic type holds a polymorphic item (usually of an interface type)
+// and serializes it to JSON as an envelope containing the registered type name
+// and the item data.
+// Deserialization uses the type name to create an item of the correct type.
+// The reg.Poly type also serializes to YAML with the type name as a node tag
+// and to XML with the type name in an xsi:type attribute.
+//
+// The reg.Inline object serializes registered objects to JSON with the type name
+// stored in a discriminator key alongside the object's own fields.
+//
+// The reg.Unknown type is used by lenient decoders as a placeholder
+// for data with a type name that is not registered.
+// The placeholder preserves the original data so it can be written back out unchanged.
+//
+// The encoding/gob package has its own type name registry.
+// Use reg.RegisterGob to copy all registrations into encoding/gob
+// or reg.NewGobRegistry to do so as each type is registered.
+//
+// # Untrusted Input
+//
+// Registry.Make will create an instance of any registered type.
+// When type names come from untrusted input use reg.NewRestricted
+// to wrap a Registry so that only specified names, names matching a pattern,
+// or types implementing a specified interface can be created.
+// Other names will result in a reg.ErrRestricted error.
+// Composite and generic type names are limited in length, nesting depth, and array size
+// so that hostile names can't create arbitrarily large types.
+//
+// # Errors
+//
+// Registry failures are returned as typed errors such as
+// reg.ErrNotRegistered, reg.ErrDuplicateType, reg.ErrDuplicateName,
+// reg.ErrPrivateType, reg.ErrAliasExists, reg.ErrNoAlias, reg.ErrNoPackagePath, and reg.ErrUnsupported.
+// These carry the name and/or type involved and can be checked with errors.As
+// or with errors.Is against a target in which empty fields match any value.
+// They are returned unchanged by reg.Alias and reg.NewRegistrar registries
+// and are still detectable when wrapped by other code.
+//
 // # Global vs local Registry
 //
 // There is a global reg.Registry object created during initialization.
@@ -70,7 +178,14 @@
 //   - reg.AddAlias
 //   - reg.Make
 //   - reg.NameFor
+//   - reg.Names
 //   - reg.Register
+//   - reg.RegisterAs
+//   - reg.RegisterFactory
+//   - reg.RegisterPrototype
+//   - reg.RemoveAlias
+//   - reg.ReplaceAlias
+//   - reg.TypeFor
 //
 // While using global resources is generally considered bad,
 // it is also good to consider why local registry objects might be needed.
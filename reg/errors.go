@@ -0,0 +1,218 @@
+package reg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Errors returned by Registry methods can be inspected with errors.As:
+//
+//	var notRegistered *reg.ErrNotRegistered
+//	if errors.As(err, &notRegistered) {
+//		fmt.Println(notRegistered.Name)
+//	}
+//
+// or matched with errors.Is against an error of the same type.
+// Zero value fields in the target match any value:
+//
+//	errors.Is(err, &reg.ErrNotRegistered{})            // any unregistered name or type
+//	errors.Is(err, &reg.ErrNotRegistered{Name: name})  // the specified name
+//
+// These errors are returned unchanged by Alias and the Registrar
+// but may be wrapped by other errors.
+
+// ErrNilItem is returned by NameFor when the item is nil.
+var ErrNilItem = errors.New("item is nil")
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrNotRegistered is returned when a type name or type is not registered.
+type ErrNotRegistered struct {
+	// Name is the type name that was not found, if a name was specified.
+	Name string
+
+	// Type is the type that was not found, if a type was specified.
+	Type reflect.Type
+
+	// Err is the underlying cause, for example an unregistered element type in a composite type name.
+	Err error
+}
+
+func (e *ErrNotRegistered) Error() string {
+	var msg string
+	if e.Name != "" {
+		msg = fmt.Sprintf("no registration for type named '%s'", e.Name)
+	} else {
+		msg = fmt.Sprintf("no registration for type %v", e.Type)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *ErrNotRegistered) Unwrap() error {
+	return e.Err
+}
+
+func (e *ErrNotRegistered) Is(target error) bool {
+	t, ok := target.(*ErrNotRegistered)
+	return ok && matchName(t.Name, e.Name) && matchType(t.Type, e.Type)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrDuplicateType is returned when registering a type that is already registered.
+type ErrDuplicateType struct {
+	// Name is the current name of the previous registration.
+	Name string
+
+	// Type is the type being registered.
+	Type reflect.Type
+}
+
+func (e *ErrDuplicateType) Error() string {
+	return fmt.Sprintf("previous registration for type %v", e.Type)
+}
+
+func (e *ErrDuplicateType) Is(target error) bool {
+	t, ok := target.(*ErrDuplicateType)
+	return ok && matchName(t.Name, e.Name) && matchType(t.Type, e.Type)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrDuplicateName is returned when a name for a type is already used by another registration.
+type ErrDuplicateName struct {
+	// Name is the name that is already in use.
+	Name string
+
+	// Type is the registered type that already uses the name.
+	Type reflect.Type
+}
+
+func (e *ErrDuplicateName) Error() string {
+	return fmt.Sprintf("name '%s' already registered for type %v", e.Name, e.Type)
+}
+
+func (e *ErrDuplicateName) Is(target error) bool {
+	t, ok := target.(*ErrDuplicateName)
+	return ok && matchName(t.Name, e.Name) && matchType(t.Type, e.Type)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrPrivateType is returned when registering an unexported type
+// (see RegisterAs and WithPrivateTypes).
+type ErrPrivateType struct {
+	// Name is the Go name of the type including the package name.
+	Name string
+
+	// Type is the type being registered.
+	Type reflect.Type
+}
+
+func (e *ErrPrivateType) Error() string {
+	return fmt.Sprintf("type '%s' is private", e.Name)
+}
+
+func (e *ErrPrivateType) Is(target error) bool {
+	t, ok := target.(*ErrPrivateType)
+	return ok && matchName(t.Name, e.Name) && matchType(t.Type, e.Type)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrAliasExists is returned when adding an alias that is already defined.
+type ErrAliasExists struct {
+	// Alias is the alias being added.
+	Alias string
+
+	// Path is the package path of the existing alias.
+	Path string
+}
+
+func (e *ErrAliasExists) Error() string {
+	return fmt.Sprintf("can't redefine alias %s", e.Alias)
+}
+
+func (e *ErrAliasExists) Is(target error) bool {
+	t, ok := target.(*ErrAliasExists)
+	return ok && matchName(t.Alias, e.Alias) && matchName(t.Path, e.Path)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrNoAlias is returned when removing or replacing an alias that is not defined.
+type ErrNoAlias struct {
+	// Alias is the alias that is not defined.
+	Alias string
+}
+
+func (e *ErrNoAlias) Error() string {
+	return fmt.Sprintf("no alias %s", e.Alias)
+}
+
+func (e *ErrNoAlias) Is(target error) bool {
+	t, ok := target.(*ErrNoAlias)
+	return ok && matchName(t.Alias, e.Alias)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrNoPackagePath is returned when a name can't be generated for a type
+// or an alias can't be defined for an example object
+// because the type has no package path (e.g. a pointer to a pointer).
+type ErrNoPackagePath struct {
+	// Alias is the alias being added, if any.
+	Alias string
+
+	// Type is the type without a package path.
+	Type reflect.Type
+}
+
+func (e *ErrNoPackagePath) Error() string {
+	if e.Alias != "" {
+		return fmt.Sprintf("no package path for alias %s (%v)", e.Alias, e.Type)
+	}
+	return fmt.Sprintf("no path for type %v", e.Type)
+}
+
+func (e *ErrNoPackagePath) Is(target error) bool {
+	t, ok := target.(*ErrNoPackagePath)
+	return ok && matchName(t.Alias, e.Alias) && matchType(t.Type, e.Type)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// ErrUnsupported is returned when a Registry doesn't implement
+// the optional interface with the requested method (e.g. FactoryRegisterer).
+type ErrUnsupported struct {
+	// Method is the name of the unsupported method.
+	Method string
+
+	// Type is the type of the Registry.
+	Type reflect.Type
+}
+
+func (e *ErrUnsupported) Error() string {
+	return fmt.Sprintf("registry %v does not support %s", e.Type, e.Method)
+}
+
+func (e *ErrUnsupported) Is(target error) bool {
+	t, ok := target.(*ErrUnsupported)
+	return ok && matchName(t.Method, e.Method) && matchType(t.Type, e.Type)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// matchName returns true if the target name is empty or the same as the name.
+func matchName(target, name string) bool {
+	return target == "" || target == name
+}
+
+// matchType returns true if the target type is nil or the same as the type.
+func matchType(target, itemType reflect.Type) bool {
+	return target == nil || target == itemType
+}
@@ -0,0 +1,131 @@
+package reg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type charlie struct{}
+
+func TestErrNotRegistered(t *testing.T) {
+	registry := NewRegistrar()
+	require.NoError(t, registry.Register(&Alpha{}))
+
+	_, err := registry.Make("[app]Bravo")
+	require.Error(t, err)
+	var notRegistered *ErrNotRegistered
+	require.True(t, errors.As(err, &notRegistered))
+	assert.Equal(t, "[app]Bravo", notRegistered.Name)
+	assert.Nil(t, notRegistered.Type)
+	assert.ErrorIs(t, err, &ErrNotRegistered{})
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: "[app]Bravo"})
+	assert.False(t, errors.Is(err, &ErrNotRegistered{Name: "[app]Charlie"}))
+	_, err = registry.(TypeFinder).TypeFor("[app]Bravo")
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: "[app]Bravo"})
+
+	_, err = registry.NameFor(&Bravo{})
+	require.True(t, errors.As(err, &notRegistered))
+	assert.Equal(t, reflect.TypeOf(Bravo{}), notRegistered.Type)
+	assert.ErrorIs(t, err, &ErrNotRegistered{Type: reflect.TypeOf(Bravo{})})
+	assert.False(t, errors.Is(err, &ErrNotRegistered{Type: reflect.TypeOf(Alpha{})}))
+
+	// The cause of a bad composite name is available.
+	_, err = registry.Make("[]" + packageName + "/Bravo")
+	require.True(t, errors.As(err, &notRegistered))
+	assert.Equal(t, "[]"+packageName+"/Bravo", notRegistered.Name)
+	assert.ErrorIs(t, notRegistered.Err, &ErrNotRegistered{Name: packageName + "/Bravo"})
+
+	err = registry.(FactoryRegisterer).RegisterFactory(packageName+"/Bravo", func() interface{} { return &Bravo{} })
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: packageName + "/Bravo"})
+}
+
+func TestErrDuplicates(t *testing.T) {
+	registry := NewRegistrar()
+	require.NoError(t, registry.(ExplicitRegisterer).RegisterAs("alpha", &Alpha{}))
+
+	err := registry.Register(&Alpha{})
+	var duplicateType *ErrDuplicateType
+	require.True(t, errors.As(err, &duplicateType))
+	assert.Equal(t, reflect.TypeOf(Alpha{}), duplicateType.Type)
+	assert.Equal(t, "alpha", duplicateType.Name)
+	assert.ErrorIs(t, err, &ErrDuplicateType{Type: reflect.TypeOf(Alpha{})})
+	assert.False(t, errors.Is(err, &ErrDuplicateType{Type: reflect.TypeOf(Bravo{})}))
+
+	err = registry.(ExplicitRegisterer).RegisterAs("alpha", &Bravo{})
+	var duplicateName *ErrDuplicateName
+	require.True(t, errors.As(err, &duplicateName))
+	assert.Equal(t, "alpha", duplicateName.Name)
+	assert.Equal(t, reflect.TypeOf(Alpha{}), duplicateName.Type)
+	assert.ErrorIs(t, err, &ErrDuplicateName{Name: "alpha"})
+	assert.False(t, errors.Is(err, &ErrDuplicateType{}))
+}
+
+func TestErrPrivateType(t *testing.T) {
+	registry := NewRegistrar()
+	err := registry.Register(&charlie{})
+	var private *ErrPrivateType
+	require.True(t, errors.As(err, &private))
+	assert.Equal(t, "reg.charlie", private.Name)
+	assert.Equal(t, reflect.TypeOf(charlie{}), private.Type)
+	assert.ErrorIs(t, err, &ErrPrivateType{Type: reflect.TypeOf(charlie{})})
+	assert.Equal(t, "type 'reg.charlie' is private", err.Error())
+}
+
+func TestErrAliases(t *testing.T) {
+	registry := NewRegistrar()
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+
+	err := registry.AddAlias("app", &Alpha{})
+	var exists *ErrAliasExists
+	require.True(t, errors.As(err, &exists))
+	assert.Equal(t, "app", exists.Alias)
+	assert.Equal(t, packageName, exists.Path)
+	assert.ErrorIs(t, err, &ErrAliasExists{Alias: "app"})
+
+	assert.ErrorIs(t, registry.(AliasRemover).RemoveAlias("other", false), &ErrNoAlias{Alias: "other"})
+	assert.ErrorIs(t, registry.(AliasRemover).ReplaceAlias("other", &Alpha{}, false), &ErrNoAlias{})
+
+	err = registry.AddAlias("int", 17)
+	var noPath *ErrNoPackagePath
+	require.True(t, errors.As(err, &noPath))
+	assert.Equal(t, "int", noPath.Alias)
+	assert.Equal(t, reflect.TypeOf(17), noPath.Type)
+	assert.ErrorIs(t, err, &ErrNoPackagePath{Alias: "int"})
+}
+
+func TestErrNilItem(t *testing.T) {
+	_, err := NewRegistrar().NameFor(nil)
+	assert.ErrorIs(t, err, ErrNilItem)
+}
+
+func TestErrorsThroughAlias(t *testing.T) {
+	registry := NewRegistry()
+	alias := NewAlias("app", registry)
+	require.NoError(t, alias.Register(&Alpha{}))
+
+	err := alias.Register(&Alpha{})
+	assert.ErrorIs(t, err, &ErrDuplicateType{Type: reflect.TypeOf(Alpha{})})
+	err = alias.RegisterAs("[app]Alpha", &Bravo{})
+	assert.ErrorIs(t, err, &ErrDuplicateName{Name: "[app]Alpha", Type: reflect.TypeOf(Alpha{})})
+	err = alias.Register(&charlie{})
+	assert.ErrorIs(t, err, &ErrPrivateType{})
+	_, err = alias.Make("[app]Bravo")
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: "[app]Bravo"})
+
+	// Errors are returned unchanged.
+	expected := registry.Register(&Alpha{})
+	assert.Equal(t, expected, alias.Register(&Alpha{}))
+	expected = registry.(ExplicitRegisterer).RegisterAs("[app]Alpha", &Bravo{})
+	assert.Equal(t, expected, alias.RegisterAs("[app]Alpha", &Bravo{}))
+	expected = registry.(FactoryRegisterer).RegisterFactory("[app]Bravo", func() interface{} { return &Bravo{} })
+	assert.Equal(t, expected, alias.RegisterFactory("[app]Bravo", func() interface{} { return &Bravo{} }))
+	expected = registry.(PrototypeRegisterer).RegisterPrototype("int", &Alpha{})
+	assert.Equal(t, expected, alias.RegisterPrototype("int", &Alpha{}))
+
+	err = NewAlias("app", registry).Register(&Bravo{})
+	assert.Equal(t, &ErrAliasExists{Alias: "app", Path: packageName}, err)
+}
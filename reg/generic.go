@@ -0,0 +1,77 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ErrTypeMismatch is returned by MakeAs when the object created for a name
+// can't be converted to the requested type.
+type ErrTypeMismatch struct {
+	// Name is the registered type name passed to MakeAs.
+	Name string
+
+	// Want is the type requested from MakeAs.
+	Want reflect.Type
+
+	// Got is the type of the object created by Registry.Make.
+	Got reflect.Type
+}
+
+func (e *ErrTypeMismatch) Error() string {
+	return fmt.Sprintf("type named '%s' is %v, not %v", e.Name, e.Got, e.Want)
+}
+
+// MakeAs creates a new instance of the type with the specified name
+// and returns it as the type specified by the type parameter.
+// The type parameter may be a pointer to the registered type,
+// an interface implemented by that pointer, or the registered type itself.
+// If the registry is nil the Singleton Registry will be used.
+func MakeAs[T any](registry Registry, name string) (T, error) {
+	var zero T
+	if registry == nil {
+		registry = singleton
+	}
+
+	item, err := registry.Make(name)
+	if err != nil {
+		return zero, err
+	}
+
+	return convertTo[T](name, item)
+}
+
+// convertTo converts an object created by Registry.Make to the specified type.
+func convertTo[T any](name string, item interface{}) (T, error) {
+	var zero T
+	if typed, ok := item.(T); ok {
+		return typed, nil
+	}
+
+	// Registry.Make returns a pointer, try the object it points to.
+	value := reflect.ValueOf(item)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		if typed, ok := value.Elem().Interface().(T); ok {
+			return typed, nil
+		}
+	}
+
+	return zero, &ErrTypeMismatch{
+		Name: name,
+		Want: reflect.TypeOf((*T)(nil)).Elem(),
+		Got:  reflect.TypeOf(item),
+	}
+}
+
+// NameOf returns the current name for the type specified by the type parameter.
+// The type parameter may be the registered type or a pointer to it.
+// Interface types have no registration and will always return an error.
+// If the registry is nil the Singleton Registry will be used.
+func NameOf[T any](registry Registry) (string, error) {
+	if registry == nil {
+		registry = singleton
+	}
+
+	var zero T
+	return registry.NameFor(zero)
+}
@@ -0,0 +1,63 @@
+package reg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeAs(t *testing.T) {
+	for _, registry := range []Registry{NewRegistry(), NewRegistrar(), NewAlias("typeUtils", NewRegistry())} {
+		require.NoError(t, registry.Register(&Alpha{}))
+		require.NoError(t, registry.Register(&Bravo{}))
+		name, err := registry.NameFor(&Alpha{})
+		require.NoError(t, err)
+
+		alphaPtr, err := MakeAs[*Alpha](registry, name)
+		require.NoError(t, err)
+		assert.NotNil(t, alphaPtr)
+		alpha, err := MakeAs[Alpha](registry, name)
+		require.NoError(t, err)
+		assert.Equal(t, Alpha{}, alpha)
+		stuff, err := MakeAs[Stuff](registry, name)
+		require.NoError(t, err)
+		assert.IsType(t, &Alpha{}, stuff)
+
+		bravo, err := MakeAs[*Bravo](registry, name)
+		require.Error(t, err)
+		assert.Nil(t, bravo)
+		var mismatch *ErrTypeMismatch
+		require.True(t, errors.As(err, &mismatch))
+		assert.Equal(t, name, mismatch.Name)
+		assert.Equal(t, reflect.TypeOf(&Bravo{}), mismatch.Want)
+		assert.Equal(t, reflect.TypeOf(&Alpha{}), mismatch.Got)
+
+		_, err = MakeAs[*Alpha](registry, "goober")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no registration")
+	}
+}
+
+func TestNameOf(t *testing.T) {
+	for _, registry := range []Registry{NewRegistry(), NewRegistrar(), NewAlias("typeUtils", NewRegistry())} {
+		require.NoError(t, registry.Register(&Alpha{}))
+		expected, err := registry.NameFor(&Alpha{})
+		require.NoError(t, err)
+
+		name, err := NameOf[Alpha](registry)
+		require.NoError(t, err)
+		assert.Equal(t, expected, name)
+		name, err = NameOf[*Alpha](registry)
+		require.NoError(t, err)
+		assert.Equal(t, expected, name)
+
+		_, err = NameOf[Bravo](registry)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no registration")
+		_, err = NameOf[Stuff](registry)
+		assert.ErrorIs(t, err, ErrNilItem)
+	}
+}
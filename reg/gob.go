@@ -0,0 +1,170 @@
+package reg
+
+import (
+	"encoding/gob"
+	"fmt"
+	"reflect"
+)
+
+// RegisterGob registers all types in the registry with the encoding/gob package
+// using their current names from the registry.
+// This allows registered types to be used in interface values in gob streams
+// with the same type names used for other forms of serialization.
+//
+// Names are fixed in encoding/gob once registered so aliases must be defined before calling RegisterGob.
+// Registering the same type again with the same name is not an error.
+// The registry must implement NameLister, otherwise an *ErrUnsupported error is returned.
+func RegisterGob(registry Registry) error {
+	if registry == nil {
+		registry = singleton
+	}
+
+	names, err := namesOf(registry)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := registerGobType(registry, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewGobRegistry returns a Registry that registers each new type with the encoding/gob package
+// using its current name from the specified registry immediately after registering it with the registry.
+// If the registry is nil the Singleton Registry will be used.
+//
+// Names are fixed in encoding/gob once registered so aliases must be defined before registering types.
+func NewGobRegistry(registry Registry) Registry {
+	if registry == nil {
+		registry = singleton
+	}
+	return &gobRegistry{
+		Registry: registry,
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// Registry implementation that mirrors registrations into encoding/gob.
+type gobRegistry struct {
+	Registry
+}
+
+// Register a type by providing an example object.
+// The type is also registered with the encoding/gob package.
+func (reg *gobRegistry) Register(example interface{}) error {
+	if err := reg.Registry.Register(example); err != nil {
+		return err
+	}
+
+	return reg.registerGob(example)
+}
+
+// RegisterAs registers a type by providing an explicit name and an example object.
+// The type is also registered with the encoding/gob package.
+func (reg *gobRegistry) RegisterAs(name string, example interface{}) error {
+	if err := registerAs(reg.Registry, name, example); err != nil {
+		return err
+	}
+
+	return reg.registerGob(example)
+}
+
+// RegisterFactory registers a factory function used by Make to create instances of a type.
+// The type is also registered with the encoding/gob package.
+func (reg *gobRegistry) RegisterFactory(example interface{}, factory func() interface{}) error {
+	if err := registerFactory(reg.Registry, example, factory); err != nil {
+		return err
+	}
+
+	// The example may be a type name.
+	if name, byName := example.(string); byName {
+		itemType, err := typeFor(reg.Registry, name)
+		if err != nil {
+			return fmt.Errorf("get type for %s: %w", name, err)
+		}
+		example = reflect.New(itemType).Interface()
+	}
+
+	return reg.registerGob(example)
+}
+
+// RegisterPrototype registers a prototype object copied by Make to create new instances.
+// The type of the prototype is also registered with the encoding/gob package.
+func (reg *gobRegistry) RegisterPrototype(name string, prototype interface{}) error {
+	if err := registerPrototype(reg.Registry, name, prototype); err != nil {
+		return err
+	}
+
+	return reg.registerGob(prototype)
+}
+
+// RemoveAlias removes an alias and the aliased names created from it.
+// Types are not removed from the encoding/gob package.
+func (reg *gobRegistry) RemoveAlias(alias string, keepNames bool) error {
+	return removeAlias(reg.Registry, alias, keepNames)
+}
+
+// ReplaceAlias redefines an existing alias to refer to the package of the example object.
+// Types are not renamed in the encoding/gob package.
+func (reg *gobRegistry) ReplaceAlias(alias string, example interface{}, keepNames bool) error {
+	return replaceAlias(reg.Registry, alias, example, keepNames)
+}
+
+// TypeFor returns the registered type with the specified name.
+func (reg *gobRegistry) TypeFor(name string) (reflect.Type, error) {
+	return typeFor(reg.Registry, name)
+}
+
+func (reg *gobRegistry) findType(name string) (reflect.Type, error) {
+	return findType(reg.Registry, name)
+}
+
+// Names returns the current names of all registered types in sorted order
+// or nil if the wrapped Registry doesn't list names.
+func (reg *gobRegistry) Names() []string {
+	names, _ := reg.names()
+	return names
+}
+
+func (reg *gobRegistry) names() ([]string, error) {
+	return namesOf(reg.Registry)
+}
+
+// registerGob registers the type of the example object with the encoding/gob package.
+func (reg *gobRegistry) registerGob(example interface{}) error {
+	name, err := reg.Registry.NameFor(example)
+	if err != nil {
+		return fmt.Errorf("get name for example: %w", err)
+	}
+
+	return registerGobType(reg.Registry, name)
+}
+
+// registerGobType registers the type with the specified name with the encoding/gob package.
+// A new zero value of the type is used so no factory, prototype, or Init method is invoked.
+func registerGobType(registry Registry, name string) error {
+	itemType, err := typeFor(registry, name)
+	if err != nil {
+		return fmt.Errorf("get type for %s: %w", name, err)
+	}
+
+	return registerGobName(name, reflect.New(itemType).Interface())
+}
+
+// registerGobName registers the item with the encoding/gob package under the specified name.
+// The gob.RegisterName function panics on conflicts, these are converted into errors.
+func registerGobName(name string, item interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("gob register %s: %v", name, r)
+		}
+	}()
+
+	gob.RegisterName(name, item)
+	return nil
+}
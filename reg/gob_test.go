@@ -0,0 +1,107 @@
+package reg
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Types registered with encoding/gob can't be unregistered,
+// so these tests use types that are not used in other tests.
+
+type GobAlpha struct {
+	Name string
+}
+
+func (g *GobAlpha) Info() string {
+	return g.Name
+}
+
+type GobBravo struct {
+	Count int
+}
+
+func (g *GobBravo) Info() string {
+	return "bravo"
+}
+
+type GobConflict struct{}
+
+// GobDelta fails to initialize.
+type GobDelta struct{}
+
+func (g *GobDelta) Init() error {
+	return errors.New("init failed")
+}
+
+func (g *GobDelta) Info() string {
+	return "delta"
+}
+
+type GobEcho struct{}
+
+func (g *GobEcho) Info() string {
+	return "echo"
+}
+
+type gobHolder struct {
+	Items []Stuff
+}
+
+func TestRegisterGob(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.AddAlias("gobTest", &GobAlpha{}))
+	require.NoError(t, registry.Register(&GobAlpha{}))
+	require.NoError(t, RegisterGob(registry))
+	// Registering again is harmless.
+	require.NoError(t, RegisterGob(registry))
+
+	gobRegistry := NewGobRegistry(registry)
+	require.NoError(t, gobRegistry.Register(&GobBravo{}))
+	assert.Equal(t, []string{"[gobTest]GobAlpha", "[gobTest]GobBravo"}, gobRegistry.(NameLister).Names())
+
+	holder := &gobHolder{Items: []Stuff{&GobAlpha{Name: "Hubert"}, &GobBravo{Count: 79}}}
+	buffer := new(bytes.Buffer)
+	require.NoError(t, gob.NewEncoder(buffer).Encode(holder))
+	assert.Contains(t, buffer.String(), "[gobTest]GobAlpha")
+	assert.Contains(t, buffer.String(), "[gobTest]GobBravo")
+	result := new(gobHolder)
+	require.NoError(t, gob.NewDecoder(buffer).Decode(result))
+	assert.Equal(t, holder, result)
+}
+
+func TestRegisterGobConflict(t *testing.T) {
+	gob.RegisterName("gobConflict", &GobConflict{})
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&GobConflict{}))
+	err := RegisterGob(registry)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gob register "+packageName+"/GobConflict")
+	assert.Contains(t, err.Error(), "registering duplicate names")
+}
+
+func TestRegisterGobWithoutMake(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.AddAlias("gobTest", &GobDelta{}))
+	gobRegistry := NewGobRegistry(registry)
+	require.NoError(t, gobRegistry.Register(&GobDelta{}))
+	calls := 0
+	require.NoError(t, gobRegistry.(FactoryRegisterer).RegisterFactory(&GobEcho{}, func() interface{} {
+		calls++
+		return &GobEcho{}
+	}))
+	// Once to check the result type during registration.
+	assert.Equal(t, 1, calls)
+	require.NoError(t, RegisterGob(registry))
+	assert.Equal(t, 1, calls)
+
+	holder := &gobHolder{Items: []Stuff{&GobDelta{}, &GobEcho{}}}
+	buffer := new(bytes.Buffer)
+	require.NoError(t, gob.NewEncoder(buffer).Encode(holder))
+	assert.Contains(t, buffer.String(), "[gobTest]GobDelta")
+	assert.Contains(t, buffer.String(), "[gobTest]GobEcho")
+}
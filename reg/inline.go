@@ -0,0 +1,166 @@
+package reg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefaultInlineKey is the default JSON key for the type name in Inline JSON.
+const DefaultInlineKey = "$type"
+
+// Inline serializes registered objects into JSON objects with the type name
+// stored in a discriminator key alongside the fields of the object:
+//
+//	{"$type": "[typeUtils]Alpha", "Name": "Hubert", "Number": 17.23}
+//
+// Only objects that serialize into JSON objects can be handled.
+// Objects with a field that serializes with the same name as the key are refused.
+type Inline struct {
+	key      string
+	registry Registry
+}
+
+// NewInline returns an Inline object that uses the specified key for the type name.
+// If the key is empty the DefaultInlineKey will be used.
+// If the registry is nil the Singleton Registry will be used.
+func NewInline(key string, registry Registry) *Inline {
+	if key == "" {
+		key = DefaultInlineKey
+	}
+	if registry == nil {
+		registry = singleton
+	}
+	return &Inline{
+		key:      key,
+		registry: registry,
+	}
+}
+
+// Key returns the JSON key used for the type name.
+func (in *Inline) Key() string {
+	return in.key
+}
+
+// Marshal the specified item into a JSON object with the type name inline.
+func (in *Inline) Marshal(item interface{}) ([]byte, error) {
+	name, err := in.registry.NameFor(item)
+	if err != nil {
+		return nil, fmt.Errorf("get name for item: %w", err)
+	}
+
+	if hasJSONField(reflect.TypeOf(item), in.key) {
+		return nil, fmt.Errorf("type %s has field conflicting with key %s", name, in.key)
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("marshal item %s: %w", name, err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) < 2 || data[0] != '{' {
+		return nil, fmt.Errorf("item %s does not marshal to JSON object", name)
+	}
+
+	// Check for conflicts from custom marshaling.
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal item %s fields: %w", name, err)
+	}
+	if _, found := fields[in.key]; found {
+		return nil, fmt.Errorf("item %s has field conflicting with key %s", name, in.key)
+	}
+
+	// Splice the type name into the front of the JSON object.
+	prefix, err := json.Marshal(map[string]string{in.key: name})
+	if err != nil {
+		return nil, fmt.Errorf("marshal type name %s: %w", name, err)
+	}
+
+	result := bytes.NewBuffer(prefix[:len(prefix)-1])
+	if len(fields) > 0 {
+		result.WriteByte(',')
+	}
+	result.Write(data[1:])
+	return result.Bytes(), nil
+}
+
+// Unmarshal a JSON object with an inline type name into a new instance of the named type.
+func (in *Inline) Unmarshal(data []byte) (interface{}, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal fields: %w", err)
+	}
+
+	rawName, found := fields[in.key]
+	if !found {
+		return nil, fmt.Errorf("no type name key %s", in.key)
+	}
+
+	var name string
+	if err := json.Unmarshal(rawName, &name); err != nil {
+		return nil, fmt.Errorf("unmarshal type name: %w", err)
+	}
+
+	item, err := in.registry.Make(name)
+	if err != nil {
+		return nil, fmt.Errorf("make item: %w", err)
+	}
+
+	delete(fields, in.key)
+	remaining, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("marshal remaining fields: %w", err)
+	}
+
+	if err := json.Unmarshal(remaining, item); err != nil {
+		return nil, fmt.Errorf("unmarshal item %s: %w", name, err)
+	}
+
+	return item, nil
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// hasJSONField returns true if the specified type is a struct (or pointer to one)
+// with a field that will be serialized to JSON using the specified key.
+func hasJSONField(itemType reflect.Type, key string) bool {
+	for itemType != nil && itemType.Kind() == reflect.Ptr {
+		itemType = itemType.Elem()
+	}
+	if itemType == nil || itemType.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < itemType.NumField(); i++ {
+		field := itemType.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			if field.Anonymous {
+				// Fields of embedded structs are promoted.
+				if hasJSONField(field.Type, key) {
+					return true
+				}
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			name = field.Name
+		}
+
+		if name == key {
+			return true
+		}
+	}
+
+	return false
+}
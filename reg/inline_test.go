@@ -0,0 +1,102 @@
+package reg
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type conflictName struct {
+	Type string `json:"$type"`
+}
+
+type ConflictTag struct {
+	Kind string `json:"kind,omitempty"`
+}
+
+type ConflictEmbedded struct {
+	ConflictTag
+	Name string
+}
+
+type Empty struct{}
+
+func TestInline(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Bravo{}))
+	require.NoError(t, registry.Register(&Empty{}))
+	inline := NewInline("", registry)
+	assert.Equal(t, DefaultInlineKey, inline.Key())
+
+	data, err := inline.Marshal(&Alpha{Name: "Hubert", Number: 17.23})
+	require.NoError(t, err)
+	assert.Equal(t, `{"$type":"[app]Alpha","Name":"Hubert","Number":17.23}`, string(data))
+	item, err := inline.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, &Alpha{Name: "Hubert", Number: 17.23}, item)
+
+	data, err = inline.Marshal(&Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"$type":"[app]Empty"}`, string(data))
+	item, err = inline.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, &Empty{}, item)
+
+	// Type name key need not be first.
+	item, err = inline.Unmarshal([]byte(`{"Iterations": 79, "$type": "[app]Bravo", "Finished": true}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Bravo{Finished: true, Iterations: 79}, item)
+}
+
+func TestInlineKey(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	require.NoError(t, registry.Register(&Bravo{}))
+	require.NoError(t, registry.Register(&ConflictTag{}))
+	require.NoError(t, registry.Register(&ConflictEmbedded{}))
+	inline := NewInline("kind", registry)
+
+	data, err := inline.Marshal(&Bravo{Iterations: 3})
+	require.NoError(t, err)
+	assert.Equal(t, `{"kind":"[app]Bravo","Finished":false,"Iterations":3}`, string(data))
+	item, err := inline.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, &Bravo{Iterations: 3}, item)
+
+	_, err = inline.Marshal(&ConflictTag{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicting with key kind")
+	_, err = inline.Marshal(&ConflictEmbedded{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicting with key kind")
+}
+
+func TestInlineErrors(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&Alpha{}))
+	inline := NewInline("", registry)
+
+	_, err := inline.Marshal(&Bravo{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no registration")
+	assert.True(t, hasJSONField(reflect.TypeOf(&conflictName{}), DefaultInlineKey))
+
+	_, err = inline.Unmarshal([]byte(`{"Name": "Hubert"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no type name key")
+	_, err = inline.Unmarshal([]byte(`{"$type": 7}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unmarshal type name")
+	_, err = inline.Unmarshal([]byte(`{"$type": "goober"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no registration for type named")
+	_, err = inline.Unmarshal([]byte(`[1, 2]`))
+	require.Error(t, err)
+	var typeErr *json.UnmarshalTypeError
+	assert.ErrorAs(t, err, &typeErr)
+}
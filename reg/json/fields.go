@@ -0,0 +1,212 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field describes a struct field to be serialized.
+type field struct {
+	// name is the JSON key for the field.
+	name string
+
+	// index is the sequence of field indexes to reach the field (see reflect.Value.FieldByIndex).
+	index []int
+
+	// omitEmpty is true if the field is not written when empty.
+	omitEmpty bool
+
+	// poly is true if the field is tagged as polymorphic.
+	poly bool
+
+	// tagged is true if the name comes from a json tag.
+	tagged bool
+}
+
+// embedded describes a struct from which fields are collected.
+type embedded struct {
+	structType reflect.Type
+	index      []int
+}
+
+// fieldsOf returns the serializable fields of the struct type in order,
+// including fields promoted from embedded structs.
+// Fields with the same name are resolved as in encoding/json:
+// the least nested field is used, then a field named by a json tag,
+// and if there is still more than one field none of them are used.
+func fieldsOf(structType reflect.Type) []*field {
+	// Collect fields in breadth first order so less nested fields come first.
+	var candidates []*field
+	current := []embedded{{structType: structType}}
+	visited := make(map[reflect.Type]bool)
+	for len(current) > 0 {
+		var next []embedded
+		for _, e := range current {
+			if visited[e.structType] {
+				continue
+			}
+			visited[e.structType] = true
+			next = collectFields(e, &candidates, next)
+		}
+		current = next
+	}
+
+	byName := make(map[string][]*field)
+	for _, f := range candidates {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	fields := make([]*field, 0, len(byName))
+	for _, f := range candidates {
+		if dominantField(byName[f.name]) == f {
+			fields = append(fields, f)
+		}
+	}
+
+	// Return fields in the order they are declared.
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+	return fields
+}
+
+// collectFields adds the fields of the struct to the candidates
+// and returns the next list of structs with any embedded structs added.
+func collectFields(e embedded, candidates *[]*field, next []embedded) []embedded {
+	for i := 0; i < e.structType.NumField(); i++ {
+		sf := e.structType.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		fieldIndex := append(append(make([]int, 0, len(e.index)+1), e.index...), i)
+
+		if sf.Anonymous && name == "" {
+			embeddedType := sf.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				next = append(next, embedded{structType: embeddedType, index: fieldIndex})
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		f := &field{
+			name:   name,
+			index:  fieldIndex,
+			poly:   sf.Tag.Get(TagName) == TagPoly,
+			tagged: name != "",
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, option := range parts[1:] {
+			if option == "omitempty" {
+				f.omitEmpty = true
+			}
+		}
+		*candidates = append(*candidates, f)
+	}
+
+	return next
+}
+
+// dominantField returns the field to use from fields with the same name in breadth first order.
+// Returns nil if no field dominates the others.
+func dominantField(fields []*field) *field {
+	depth := len(fields[0].index)
+	var dominant *field
+	count := 0
+	for _, f := range fields {
+		if len(f.index) > depth {
+			break
+		}
+		count++
+		if f.tagged {
+			if dominant != nil {
+				// More than one tagged field at the same depth.
+				return nil
+			}
+			dominant = f
+		}
+	}
+
+	if count == 1 {
+		return fields[0]
+	}
+	return dominant
+}
+
+// lessIndex returns true if the first field index sequence comes before the second.
+func lessIndex(first, second []int) bool {
+	for i, x := range first {
+		if i >= len(second) {
+			return false
+		}
+		if x != second[i] {
+			return x < second[i]
+		}
+	}
+	return len(first) < len(second)
+}
+
+// fieldByIndex returns the nested field of the struct value.
+// Returns false if an embedded struct pointer on the way to the field is nil.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value, true
+}
+
+// fieldForSet returns the nested field of the struct value,
+// allocating any nil embedded struct pointers on the way to the field.
+func fieldForSet(value reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !value.CanSet() {
+					return reflect.Value{}, fmt.Errorf("can't set embedded pointer to unexported struct %v", value.Type().Elem())
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value, nil
+}
+
+// isEmpty returns true if the value is considered empty for the omitempty tag option.
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,519 @@
+// Package json provides JSON serialization of objects containing polymorphic values.
+//
+// The Marshal and Unmarshal functions walk the object via reflection.
+// Struct fields tagged with `reg:"poly"` are treated as polymorphic.
+// If such a field is an interface, or is a slice, array, or map of interfaces,
+// each interface value with a registered type is wrapped in the same JSON envelope
+// used by reg.Poly and the codec package:
+//
+//	{"type": "[typeUtils]Alpha", "data": {"Name": "Hubert", "Number": 17.23}}
+//
+// When unmarshaling polymorphic fields each envelope is used to create
+// a new instance of the named type via reg.Registry.Make.
+// Interface values with unregistered types are serialized normally,
+// which will only work when unmarshaling into an empty interface.
+// Values of composite and predeclared types (e.g. string or []int) are unregistered
+// unless the Registry was created with the reg.WithDerivedNames option.
+//
+// For example:
+//
+//	type Holder struct {
+//		Single Stuff            `reg:"poly"`
+//		List   []Stuff          `reg:"poly"`
+//		Lookup map[string]Stuff `json:"lookup" reg:"poly"`
+//	}
+//
+// Field names, the json tag options "-" and "omitempty",
+// and the promotion of fields from embedded structs (including conflicting names)
+// are handled as in encoding/json.
+// Types that implement json.Marshaler or json.Unmarshaler are handled by those methods.
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+const (
+	// TagName is the struct tag used to mark polymorphic fields.
+	TagName = "reg"
+
+	// TagPoly is the TagName value that marks polymorphic fields.
+	TagPoly = "poly"
+)
+
+// Marshal returns the JSON encoding of v using the Singleton Registry.
+func Marshal(v interface{}) ([]byte, error) {
+	return NewConverter(nil).Marshal(v)
+}
+
+// Unmarshal parses JSON data into v using the Singleton Registry.
+func Unmarshal(data []byte, v interface{}) error {
+	return NewConverter(nil).Unmarshal(data, v)
+}
+
+// Converter marshals and unmarshals objects with polymorphic fields using a specific Registry.
+type Converter struct {
+	registry reg.Registry
+	lenient  bool
+}
+
+// NewConverter returns a Converter that uses the specified Registry.
+// If the registry is nil the Singleton Registry will be used.
+func NewConverter(registry reg.Registry) *Converter {
+	if registry == nil {
+		registry = reg.Singleton()
+	}
+	return &Converter{
+		registry: registry,
+	}
+}
+
+// Lenient returns a copy of the Converter that unmarshals envelopes
+// with unregistered type names into *reg.Unknown placeholders instead of failing.
+// Placeholders are only used for fields that can hold a *reg.Unknown,
+// such as interface{} fields, otherwise the error is returned as usual.
+// When marshaled the placeholder is written back out exactly as it was read.
+func (c *Converter) Lenient() *Converter {
+	return &Converter{
+		registry: c.registry,
+		lenient:  true,
+	}
+}
+
+// Marshal returns the JSON encoding of v.
+func (c *Converter) Marshal(v interface{}) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if err := c.encode(buffer, reflect.ValueOf(v), false); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Unmarshal parses JSON data into v, which must be a non-nil pointer.
+func (c *Converter) Unmarshal(data []byte, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("unmarshal target must be non-nil pointer, not %v", reflect.TypeOf(v))
+	}
+	return c.decode(data, value.Elem(), false)
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// envelope is the JSON envelope for polymorphic values.
+type envelope struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+var (
+	jsonNull        = []byte("null")
+	unknownType     = reflect.TypeOf((*reg.Unknown)(nil))
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textKeyType     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encode writes the JSON for the value to the buffer.
+// If poly is true interface values with registered types are wrapped in envelopes.
+func (c *Converter) encode(buffer *bytes.Buffer, value reflect.Value, poly bool) error {
+	if !value.IsValid() {
+		buffer.Write(jsonNull)
+		return nil
+	}
+
+	valueType := value.Type()
+	if valueType == unknownType && !value.IsNil() {
+		// Write the raw data directly as encoding/json would compact it.
+		buffer.Write(value.Interface().(*reg.Unknown).Raw)
+		return nil
+	}
+
+	if valueType.Implements(marshalerType) && valueType.Kind() != reflect.Interface {
+		if valueType.Kind() == reflect.Ptr && value.IsNil() {
+			buffer.Write(jsonNull)
+			return nil
+		}
+		return c.encodeStandard(buffer, value)
+	} else if value.CanAddr() && reflect.PtrTo(valueType).Implements(marshalerType) {
+		return c.encodeStandard(buffer, value.Addr())
+	}
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			buffer.Write(jsonNull)
+			return nil
+		} else if poly {
+			return c.encodePoly(buffer, value.Elem())
+		}
+		return c.encode(buffer, value.Elem(), false)
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			buffer.Write(jsonNull)
+			return nil
+		}
+		return c.encode(buffer, value.Elem(), poly)
+
+	case reflect.Struct:
+		return c.encodeStruct(buffer, value)
+
+	case reflect.Slice:
+		if value.IsNil() {
+			buffer.Write(jsonNull)
+			return nil
+		} else if valueType.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are base64 encoded.
+			return c.encodeStandard(buffer, value)
+		}
+		return c.encodeArray(buffer, value, poly)
+
+	case reflect.Array:
+		return c.encodeArray(buffer, value, poly)
+
+	case reflect.Map:
+		if value.IsNil() {
+			buffer.Write(jsonNull)
+			return nil
+		}
+		return c.encodeMap(buffer, value, poly)
+
+	default:
+		return c.encodeStandard(buffer, value)
+	}
+}
+
+// encodePoly writes the value in an envelope if its type is registered.
+func (c *Converter) encodePoly(buffer *bytes.Buffer, value reflect.Value) error {
+	name, err := c.registry.NameFor(value.Interface())
+	if err != nil {
+		// Unregistered types are written normally.
+		return c.encode(buffer, value, false)
+	}
+
+	data := new(bytes.Buffer)
+	if err := c.encode(data, value, false); err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+
+	return c.encodeStandard(buffer, reflect.ValueOf(&envelope{Type: name, Data: data.Bytes()}))
+}
+
+func (c *Converter) encodeStruct(buffer *bytes.Buffer, value reflect.Value) error {
+	buffer.WriteByte('{')
+	first := true
+	for _, f := range fieldsOf(value.Type()) {
+		field, ok := fieldByIndex(value, f.index)
+		if !ok || (f.omitEmpty && isEmpty(field)) {
+			continue
+		}
+
+		if !first {
+			buffer.WriteByte(',')
+		}
+		first = false
+		if err := c.encodeStandard(buffer, reflect.ValueOf(f.name)); err != nil {
+			return err
+		}
+		buffer.WriteByte(':')
+		if err := c.encode(buffer, field, f.poly); err != nil {
+			return fmt.Errorf("encode field %s: %w", f.name, err)
+		}
+	}
+	buffer.WriteByte('}')
+	return nil
+}
+
+func (c *Converter) encodeArray(buffer *bytes.Buffer, value reflect.Value, poly bool) error {
+	buffer.WriteByte('[')
+	for i := 0; i < value.Len(); i++ {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		if err := c.encode(buffer, value.Index(i), poly); err != nil {
+			return fmt.Errorf("encode element %d: %w", i, err)
+		}
+	}
+	buffer.WriteByte(']')
+	return nil
+}
+
+func (c *Converter) encodeMap(buffer *bytes.Buffer, value reflect.Value, poly bool) error {
+	keyType := value.Type().Key()
+	keys := make([]string, 0, value.Len())
+	lookup := make(map[string]reflect.Value, value.Len())
+	for _, key := range value.MapKeys() {
+		var keyStr string
+		switch {
+		case keyType.Kind() == reflect.String:
+			keyStr = key.String()
+		case keyType.Implements(textKeyType):
+			text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return fmt.Errorf("marshal map key: %w", err)
+			}
+			keyStr = string(text)
+		case key.CanInt():
+			keyStr = strconv.FormatInt(key.Int(), 10)
+		case key.CanUint():
+			keyStr = strconv.FormatUint(key.Uint(), 10)
+		default:
+			return fmt.Errorf("unsupported map key type %v", keyType)
+		}
+		keys = append(keys, keyStr)
+		lookup[keyStr] = value.MapIndex(key)
+	}
+	sort.Strings(keys)
+
+	buffer.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		if err := c.encodeStandard(buffer, reflect.ValueOf(key)); err != nil {
+			return err
+		}
+		buffer.WriteByte(':')
+		if err := c.encode(buffer, lookup[key], poly); err != nil {
+			return fmt.Errorf("encode map value %s: %w", key, err)
+		}
+	}
+	buffer.WriteByte('}')
+	return nil
+}
+
+// encodeStandard writes the value using encoding/json.
+func (c *Converter) encodeStandard(buffer *bytes.Buffer, value reflect.Value) error {
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return err
+	}
+	buffer.Write(data)
+	return nil
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// decode parses the JSON data into the value, which must be settable.
+// If poly is true interface values are read from envelopes.
+func (c *Converter) decode(data []byte, value reflect.Value, poly bool) error {
+	isNull := bytes.Equal(bytes.TrimSpace(data), jsonNull)
+	valueType := value.Type()
+	if valueType.Kind() != reflect.Interface && reflect.PtrTo(valueType).Implements(unmarshalerType) {
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if isNull {
+			value.Set(reflect.Zero(valueType))
+			return nil
+		} else if poly {
+			return c.decodePoly(data, value)
+		}
+		return json.Unmarshal(data, value.Addr().Interface())
+
+	case reflect.Ptr:
+		if isNull {
+			value.Set(reflect.Zero(valueType))
+			return nil
+		}
+		if value.IsNil() {
+			value.Set(reflect.New(valueType.Elem()))
+		}
+		return c.decode(data, value.Elem(), poly)
+
+	case reflect.Struct:
+		if isNull {
+			return nil
+		}
+		return c.decodeStruct(data, value)
+
+	case reflect.Slice:
+		if isNull {
+			value.Set(reflect.Zero(valueType))
+			return nil
+		} else if valueType.Elem().Kind() == reflect.Uint8 {
+			return json.Unmarshal(data, value.Addr().Interface())
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(valueType, len(elements), len(elements))
+		for i, element := range elements {
+			if err := c.decode(element, slice.Index(i), poly); err != nil {
+				return fmt.Errorf("decode element %d: %w", i, err)
+			}
+		}
+		value.Set(slice)
+		return nil
+
+	case reflect.Array:
+		if isNull {
+			return nil
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(data, &elements); err != nil {
+			return err
+		}
+		for i := 0; i < value.Len(); i++ {
+			if i < len(elements) {
+				if err := c.decode(elements[i], value.Index(i), poly); err != nil {
+					return fmt.Errorf("decode element %d: %w", i, err)
+				}
+			} else {
+				value.Index(i).Set(reflect.Zero(valueType.Elem()))
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if isNull {
+			value.Set(reflect.Zero(valueType))
+			return nil
+		}
+		return c.decodeMap(data, value, poly)
+
+	default:
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+}
+
+// decodePoly parses an envelope into a new instance of the named type
+// and stores it in the interface value.
+// If the data is not an envelope it is parsed normally.
+func (c *Converter) decodePoly(data []byte, value reflect.Value) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not a JSON object so not an envelope.
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+	if _, found := fields["type"]; !found {
+		return json.Unmarshal(data, value.Addr().Interface())
+	}
+
+	env := new(envelope)
+	if err := json.Unmarshal(data, env); err != nil {
+		return fmt.Errorf("unmarshal envelope: %w", err)
+	}
+
+	item, err := c.registry.Make(env.Type)
+	if err != nil {
+		var notRegistered *reg.ErrNotRegistered
+		if c.lenient && errors.As(err, &notRegistered) && unknownType.AssignableTo(value.Type()) {
+			value.Set(reflect.ValueOf(reg.NewUnknown(env.Type, data)))
+			return nil
+		}
+		return fmt.Errorf("make item: %w", err)
+	}
+
+	itemValue := reflect.ValueOf(item)
+	if len(env.Data) > 0 {
+		if err := c.decode(env.Data, itemValue.Elem(), false); err != nil {
+			return fmt.Errorf("decode %s: %w", env.Type, err)
+		}
+	}
+
+	switch {
+	case itemValue.Type().AssignableTo(value.Type()):
+		value.Set(itemValue)
+	case itemValue.Elem().Type().AssignableTo(value.Type()):
+		value.Set(itemValue.Elem())
+	default:
+		return &reg.ErrTypeMismatch{Name: env.Type, Want: value.Type(), Got: itemValue.Type()}
+	}
+
+	return nil
+}
+
+func (c *Converter) decodeStruct(data []byte, value reflect.Value) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for _, f := range fieldsOf(value.Type()) {
+		raw, found := fields[f.name]
+		if !found {
+			// Match keys case-insensitively as does encoding/json.
+			for key, r := range fields {
+				if strings.EqualFold(key, f.name) {
+					raw, found = r, true
+					break
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+
+		field, err := fieldForSet(value, f.index)
+		if err != nil {
+			return fmt.Errorf("decode field %s: %w", f.name, err)
+		}
+		if err := c.decode(raw, field, f.poly); err != nil {
+			return fmt.Errorf("decode field %s: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Converter) decodeMap(data []byte, value reflect.Value, poly bool) error {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	mapType := value.Type()
+	keyType := mapType.Key()
+	if value.IsNil() {
+		value.Set(reflect.MakeMapWithSize(mapType, len(entries)))
+	}
+
+	for keyStr, raw := range entries {
+		key := reflect.New(keyType).Elem()
+		switch {
+		case keyType.Kind() == reflect.String:
+			key.SetString(keyStr)
+		case reflect.PtrTo(keyType).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()):
+			if err := key.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(keyStr)); err != nil {
+				return fmt.Errorf("unmarshal map key %s: %w", keyStr, err)
+			}
+		case key.CanInt():
+			n, err := strconv.ParseInt(keyStr, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parse map key %s: %w", keyStr, err)
+			}
+			key.SetInt(n)
+		case key.CanUint():
+			n, err := strconv.ParseUint(keyStr, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parse map key %s: %w", keyStr, err)
+			}
+			key.SetUint(n)
+		default:
+			return fmt.Errorf("unsupported map key type %v", keyType)
+		}
+
+		element := reflect.New(mapType.Elem()).Elem()
+		if err := c.decode(raw, element, poly); err != nil {
+			return fmt.Errorf("decode map value %s: %w", keyStr, err)
+		}
+		value.SetMapIndex(key, element)
+	}
+
+	return nil
+}
@@ -0,0 +1,325 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+type jsonTestSuite struct {
+	suite.Suite
+	converter *Converter
+}
+
+func (suite *jsonTestSuite) SetupTest() {
+	registry := reg.NewRegistry()
+	suite.Require().NoError(registry.AddAlias("test", &Alpha{}))
+	suite.Require().NoError(registry.Register(&Alpha{}))
+	suite.Require().NoError(registry.Register(&Bravo{}))
+	suite.Require().NoError(registry.Register(&Nested{}))
+	suite.converter = NewConverter(registry)
+}
+
+func TestJSONSuite(t *testing.T) {
+	suite.Run(t, new(jsonTestSuite))
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+type Stuff interface {
+	Info() string
+}
+
+type Alpha struct {
+	Name   string
+	Number float32 `json:"number,omitempty"`
+}
+
+func (a *Alpha) Info() string {
+	return fmt.Sprintf("%s: %f", a.Name, a.Number)
+}
+
+type Bravo struct {
+	Finished   bool
+	Iterations int
+}
+
+func (b *Bravo) Info() string {
+	return fmt.Sprintf("%t: %d", b.Finished, b.Iterations)
+}
+
+// Nested is a registered type with its own polymorphic field.
+type Nested struct {
+	Inner Stuff `reg:"poly"`
+}
+
+func (n *Nested) Info() string {
+	return "nested"
+}
+
+type Embedded struct {
+	Embedded Stuff `reg:"poly"`
+}
+
+type Holder struct {
+	*Embedded
+	Single  Stuff            `reg:"poly"`
+	List    []Stuff          `json:"list" reg:"poly"`
+	Array   [2]Stuff         `reg:"poly"`
+	Lookup  map[string]Stuff `json:"lookup,omitempty" reg:"poly"`
+	Numbers map[int]Stuff    `reg:"poly"`
+	Ptr     *Stuff           `reg:"poly"`
+	Any     interface{}      `reg:"poly"`
+	Plain   interface{}
+	Poly    reg.Poly[Stuff]
+	Skip    string `json:"-"`
+	private string
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+func (suite *jsonTestSuite) TestMarshal() {
+	data, err := suite.converter.Marshal(&Holder{
+		Single: &Alpha{Name: "Hubert", Number: 17.23},
+		List:   []Stuff{&Bravo{Iterations: 3}, nil},
+		Skip:   "skip",
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"Single":{"type":"[test]Alpha","data":{"Name":"Hubert","number":17.23}},`+
+		`"list":[{"type":"[test]Bravo","data":{"Finished":false,"Iterations":3}},null],`+
+		`"Array":[null,null],"Numbers":null,"Ptr":null,"Any":null,"Plain":null,"Poly":null}`, string(data))
+}
+
+func (suite *jsonTestSuite) TestCycle() {
+	var stuff Stuff = &Bravo{Finished: true}
+	holder := &Holder{
+		Embedded: &Embedded{Embedded: &Alpha{Name: "Embedded"}},
+		Single:   &Alpha{Name: "Hubert", Number: 17.23},
+		List:     []Stuff{&Bravo{Iterations: 3}, &Nested{Inner: &Alpha{Name: "Inner"}}, nil},
+		Array:    [2]Stuff{&Alpha{Name: "Array"}},
+		Lookup:   map[string]Stuff{"alpha": &Alpha{Name: "Wilbur"}, "bravo": &Bravo{Iterations: 79}},
+		Numbers:  map[int]Stuff{17: &Alpha{Name: "Seventeen"}},
+		Ptr:      &stuff,
+		Any:      &Alpha{Name: "Any"},
+		Plain:    "plain",
+		Poly:     reg.Poly[Stuff]{Item: &Alpha{}},
+	}
+	holder.Poly.Registry = suite.converter.registry
+	data, err := suite.converter.Marshal(holder)
+	suite.Require().NoError(err)
+
+	result := &Holder{Poly: reg.Poly[Stuff]{Registry: suite.converter.registry}}
+	suite.Require().NoError(suite.converter.Unmarshal(data, result))
+	suite.Assert().Equal(holder, result)
+}
+
+func (suite *jsonTestSuite) TestStandardCompatible() {
+	// Without polymorphic fields the output should match encoding/json.
+	type plain struct {
+		Name    string
+		Numbers []int          `json:"numbers"`
+		Lookup  map[string]int `json:",omitempty"`
+		Bytes   []byte
+		Alpha   *Alpha
+	}
+	item := &plain{Name: "plain", Numbers: []int{1, 2}, Bytes: []byte("bytes"), Alpha: &Alpha{Name: "Alpha"}}
+	expected, err := json.Marshal(item)
+	suite.Require().NoError(err)
+	data, err := suite.converter.Marshal(item)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(string(expected), string(data))
+	result := new(plain)
+	suite.Require().NoError(suite.converter.Unmarshal(data, result))
+	suite.Assert().Equal(item, result)
+}
+
+type Inner struct {
+	Name   string
+	Tagged string `json:"Label"`
+	Both   string
+	Deeper
+}
+
+type Other struct {
+	Both  string
+	Label string
+}
+
+type Deeper struct {
+	Name  string
+	Depth int
+}
+
+func (suite *jsonTestSuite) TestEmbeddedConflicts() {
+	// Field names from embedded structs are resolved as in encoding/json.
+	type outer struct {
+		Inner
+		Other
+		Name string
+	}
+	item := &outer{
+		Inner: Inner{Name: "inner", Tagged: "tagged", Both: "inner both", Deeper: Deeper{Name: "deeper", Depth: 2}},
+		Other: Other{Both: "other both", Label: "other label"},
+		Name:  "outer",
+	}
+	expected, err := json.Marshal(item)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"Label":"tagged","Depth":2,"Name":"outer"}`, string(expected))
+	data, err := suite.converter.Marshal(item)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(string(expected), string(data))
+
+	result := new(outer)
+	suite.Require().NoError(suite.converter.Unmarshal(data, result))
+	suite.Assert().Equal(&outer{
+		Inner: Inner{Tagged: "tagged", Deeper: Deeper{Depth: 2}},
+		Name:  "outer",
+	}, result)
+}
+
+func (suite *jsonTestSuite) TestUnregistered() {
+	type other struct {
+		A string
+	}
+	type unregistered struct {
+		Any interface{} `reg:"poly"`
+	}
+	data, err := suite.converter.Marshal(&unregistered{Any: other{A: "b"}})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"Any":{"A":"b"}}`, string(data))
+	result := new(unregistered)
+	suite.Require().NoError(suite.converter.Unmarshal(data, result))
+	suite.Assert().Equal(map[string]interface{}{"A": "b"}, result.Any)
+}
+
+func (suite *jsonTestSuite) TestBuiltins() {
+	type builtins struct {
+		Text    interface{} `reg:"poly"`
+		Number  interface{} `reg:"poly"`
+		List    interface{} `reg:"poly"`
+		Numbers []int       `reg:"poly"`
+	}
+	item := &builtins{Text: "hello", Number: 42, List: []string{"a"}, Numbers: []int{1, 2}}
+
+	// Values of unregistered types are serialized normally.
+	data, err := suite.converter.Marshal(item)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"Text":"hello","Number":42,"List":["a"],"Numbers":[1,2]}`, string(data))
+	result := new(builtins)
+	suite.Require().NoError(suite.converter.Unmarshal(data, result))
+	suite.Assert().Equal(&builtins{Text: "hello", Number: 42.0, List: []interface{}{"a"}, Numbers: []int{1, 2}}, result)
+
+	// Derived names are written if the registry provides them.
+	converter := NewConverter(reg.NewRegistry(reg.WithDerivedNames()))
+	data, err = converter.Marshal(item)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"Text":{"type":"string","data":"hello"},"Number":{"type":"int","data":42},`+
+		`"List":{"type":"[]string","data":["a"]},"Numbers":[1,2]}`, string(data))
+	result = new(builtins)
+	suite.Require().NoError(converter.Unmarshal(data, result))
+	text, number := "hello", 42
+	suite.Assert().Equal(&builtins{Text: &text, Number: &number, List: &[]string{"a"}, Numbers: []int{1, 2}}, result)
+}
+
+func (suite *jsonTestSuite) TestUnmarshalErrors() {
+	holder := new(Holder)
+	err := suite.converter.Unmarshal([]byte(`{"Single":{"type":"[test]Charlie","data":{}}}`), holder)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "decode field Single")
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+	err = suite.converter.Unmarshal([]byte(`{"list":[{"type":"[test]Alpha","data":{"Name":1}}]}`), holder)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "decode element 0")
+	err = suite.converter.Unmarshal([]byte(`{}`), *holder)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "non-nil pointer")
+
+	type mismatch struct {
+		Alpha *Alpha `reg:"poly"`
+		Bravo *Bravo `reg:"poly"`
+	}
+	err = suite.converter.Unmarshal([]byte(`{"Alpha":{"Name":"Hubert"}}`), new(mismatch))
+	suite.Require().NoError(err)
+	type wrong struct {
+		Alpha fmt.Stringer `reg:"poly"`
+	}
+	err = suite.converter.Unmarshal([]byte(`{"Alpha":{"type":"[test]Alpha","data":{}}}`), new(wrong))
+	suite.Require().Error(err)
+	var mismatchErr *reg.ErrTypeMismatch
+	suite.Assert().ErrorAs(err, &mismatchErr)
+}
+
+func (suite *jsonTestSuite) TestSingleton() {
+	previous := reg.Singleton()
+	defer reg.SetSingleton(previous)
+	reg.SetSingleton(suite.converter.registry)
+
+	data, err := Marshal(&Nested{Inner: &Bravo{Iterations: 1}})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"Inner":{"type":"[test]Bravo","data":{"Finished":false,"Iterations":1}}}`, string(data))
+	result := new(Nested)
+	suite.Require().NoError(Unmarshal(data, result))
+	suite.Assert().Equal(&Nested{Inner: &Bravo{Iterations: 1}}, result)
+}
+
+func (suite *jsonTestSuite) TestLenient() {
+	type document struct {
+		Known   Stuff         `reg:"poly"`
+		Unknown interface{}   `reg:"poly"`
+		List    []interface{} `reg:"poly"`
+	}
+	data := []byte(`{"Known":{"type":"[test]Alpha","data":{"Name":"Hubert"}},` +
+		`"Unknown":{ "type" : "[test]Charlie", "data" : {"Text": "spaced out"} },` +
+		`"List":[{"type":"[test]Bravo","data":{"Finished":true,"Iterations":0}},{"type":"[test]Delta","data":[1, 2]}]}`)
+
+	err := suite.converter.Unmarshal(data, new(document))
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+
+	lenient := suite.converter.Lenient()
+	result := new(document)
+	suite.Require().NoError(lenient.Unmarshal(data, result))
+	suite.Assert().Equal(&Alpha{Name: "Hubert"}, result.Known)
+	unknown, ok := result.Unknown.(*reg.Unknown)
+	suite.Require().True(ok)
+	suite.Assert().Equal("[test]Charlie", unknown.Name)
+	suite.Require().Len(result.List, 2)
+	suite.Assert().Equal(&Bravo{Finished: true}, result.List[0])
+	unknown, ok = result.List[1].(*reg.Unknown)
+	suite.Require().True(ok)
+	suite.Assert().Equal("[test]Delta", unknown.Name)
+
+	again, err := lenient.Marshal(result)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(string(data), string(again))
+
+	// Unknown types can't be stored in fields that can't hold a placeholder.
+	err = lenient.Unmarshal([]byte(`{"Known":{"type":"[test]Charlie","data":{}}}`), new(document))
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+}
+
+// Failing is a registered type that fails to initialize.
+type Failing struct{}
+
+func (f *Failing) Init() error {
+	return errors.New("init failed")
+}
+
+func (suite *jsonTestSuite) TestLenientInitError() {
+	registry := reg.NewRegistry()
+	suite.Require().NoError(registry.AddAlias("test", &Alpha{}))
+	suite.Require().NoError(registry.Register(&Failing{}))
+	var document struct {
+		Item interface{} `reg:"poly"`
+	}
+	err := NewConverter(registry).Lenient().Unmarshal([]byte(`{"Item":{"type":"[test]Failing","data":{}}}`), &document)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "init failed")
+	suite.Assert().Nil(document.Item)
+}
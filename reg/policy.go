@@ -0,0 +1,92 @@
+package reg
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// NamePolicy chooses the current name for a registered type from its possible names.
+// The first name is the full name, the rest are aliased names in sorted order.
+// The policy returns one of the names or an empty string to defer to the next policy.
+// Types registered with explicit names (see RegisterAs and Namer) are not subject to name policies.
+type NamePolicy func(itemType reflect.Type, names []string) string
+
+// WithNamePolicy configures a Registry to choose current names for registered types
+// using the specified policies.
+// The first name returned by a policy is used.
+// If no policy returns one of the possible names ShortestName is used.
+//
+// Name policies are applied whenever the names of a type change,
+// for example when an alias is added or removed.
+func WithNamePolicy(policies ...NamePolicy) Option {
+	return func(reg *registry) {
+		reg.policies = append(reg.policies, policies...)
+	}
+}
+
+// ShortestName is the default NamePolicy.
+// It returns the shortest name, most likely an aliased name if there are any.
+// Names of the same length are ordered lexically so the result is always the same.
+func ShortestName(_ reflect.Type, names []string) string {
+	var shortest string
+	for _, name := range names {
+		if shortest == "" || len(name) < len(shortest) || (len(name) == len(shortest) && name < shortest) {
+			shortest = name
+		}
+	}
+	return shortest
+}
+
+// AliasPriority returns a NamePolicy that chooses the aliased name for the first
+// of the specified aliases that applies to the type.
+func AliasPriority(aliases ...string) NamePolicy {
+	return func(_ reflect.Type, names []string) string {
+		for _, alias := range aliases {
+			prefix := "[" + alias + "]"
+			for _, name := range names {
+				if strings.HasPrefix(name, prefix) {
+					return name
+				}
+			}
+		}
+		return ""
+	}
+}
+
+// PreferNames returns a NamePolicy that chooses the first of the specified names
+// that is a possible name for the type.
+// This can be used to choose specific names (e.g. a full name) for specific types.
+func PreferNames(preferred ...string) NamePolicy {
+	return func(_ reflect.Type, names []string) string {
+		for _, prefer := range preferred {
+			for _, name := range names {
+				if name == prefer {
+					return name
+				}
+			}
+		}
+		return ""
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// chooseName chooses the current name for the type from the full name and aliased names.
+func (reg *registry) chooseName(itemType reflect.Type, names []string) string {
+	// Keep aliased names in sorted order so policies always see the same list.
+	names = append(make([]string, 0, len(names)), names...)
+	sort.Strings(names[1:])
+
+	for _, policy := range reg.policies {
+		if name := policy(itemType, names); name != "" {
+			for _, possible := range names {
+				if name == possible {
+					return name
+				}
+			}
+		}
+	}
+
+	return ShortestName(itemType, names)
+}
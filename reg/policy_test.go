@@ -0,0 +1,60 @@
+package reg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortestName(t *testing.T) {
+	assert.Equal(t, "[a]X", ShortestName(nil, []string{"example.com/foo/X", "[b]X", "[a]X", "[long]X"}))
+	assert.Equal(t, "example.com/foo/X", ShortestName(nil, []string{"example.com/foo/X"}))
+	assert.Equal(t, "", ShortestName(nil, nil))
+}
+
+func TestNamePolicyStable(t *testing.T) {
+	for i := 0; i < 25; i++ {
+		registry := NewRegistry()
+		for _, alias := range []string{"d", "b", "c", "a", "e"} {
+			require.NoError(t, registry.AddAlias(alias, &Alpha{}))
+		}
+		require.NoError(t, registry.Register(&Alpha{}))
+		require.NoError(t, registry.Register(&Bravo{}))
+		require.NoError(t, registry.AddAlias("f", &Alpha{}))
+		assert.Equal(t, []string{"[a]Alpha", "[a]Bravo"}, registry.(NameLister).Names())
+	}
+}
+
+func TestAliasPriority(t *testing.T) {
+	registry := NewRegistry(WithNamePolicy(AliasPriority("missing", "long", "short")))
+	require.NoError(t, registry.AddAlias("short", &Alpha{}))
+	require.NoError(t, registry.Register(&Alpha{}))
+	name, err := registry.NameFor(&Alpha{})
+	require.NoError(t, err)
+	assert.Equal(t, "[short]Alpha", name)
+
+	// Policy is applied again when aliases change.
+	require.NoError(t, registry.AddAlias("long", &Alpha{}))
+	name, err = registry.NameFor(&Alpha{})
+	require.NoError(t, err)
+	assert.Equal(t, "[long]Alpha", name)
+	require.NoError(t, registry.(AliasRemover).RemoveAlias("long", false))
+	name, err = registry.NameFor(&Alpha{})
+	require.NoError(t, err)
+	assert.Equal(t, "[short]Alpha", name)
+}
+
+func TestPreferNames(t *testing.T) {
+	registry := NewRegistry(WithNamePolicy(
+		PreferNames(packageName+"/Bravo"),
+		func(itemType reflect.Type, names []string) string {
+			// Bad policy returns a name that is not possible.
+			return "bad"
+		}))
+	require.NoError(t, registry.AddAlias("x", &Alpha{}))
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Bravo{}))
+	assert.Equal(t, []string{"[x]Alpha", packageName + "/Bravo"}, registry.(NameLister).Names())
+}
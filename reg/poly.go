@@ -0,0 +1,116 @@
+package reg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Poly holds a polymorphic item for serialization into JSON.
+// The item is typically declared as an interface type
+// with the concrete type of the item registered in a Registry.
+//
+// The item is marshaled into a JSON envelope containing the registered name
+// of the item's type and the JSON for the item itself:
+//
+//	{"type": "[typeUtils]Alpha", "data": {"Name": "Hubert", "Number": 17.23}}
+//
+// When unmarshaling the type name is used to create a new instance
+// of the appropriate type via Registry.Make into which the data is unmarshaled.
+// A nil item is marshaled as JSON null.
+type Poly[I any] struct {
+	// Item is the polymorphic object.
+	Item I
+
+	// Registry used to name and create items.
+	// If nil the Singleton Registry will be used.
+	Registry Registry
+}
+
+// NewPoly returns a Poly object containing the specified item.
+// If the registry is nil the Singleton Registry will be used.
+func NewPoly[I any](item I, registry Registry) *Poly[I] {
+	return &Poly[I]{
+		Item:     item,
+		Registry: registry,
+	}
+}
+
+// Make sure the interfaces are satisfied at compile time.
+var _ json.Marshaler = Poly[interface{}]{}
+var _ json.Unmarshaler = &Poly[interface{}]{}
+
+// polyJSON is the JSON envelope used to serialize Poly objects.
+type polyJSON struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+var jsonNull = []byte("null")
+
+// MarshalJSON marshals the item into a JSON envelope with its type name.
+func (p Poly[I]) MarshalJSON() ([]byte, error) {
+	if isNilItem(p.Item) {
+		return jsonNull, nil
+	}
+
+	name, err := p.registry().NameFor(p.Item)
+	if err != nil {
+		return nil, fmt.Errorf("get name for item: %w", err)
+	}
+
+	data, err := json.Marshal(p.Item)
+	if err != nil {
+		return nil, fmt.Errorf("marshal item %s: %w", name, err)
+	}
+
+	return json.Marshal(&polyJSON{Type: name, Data: data})
+}
+
+// UnmarshalJSON unmarshals a JSON envelope into a new item of the named type.
+func (p *Poly[I]) UnmarshalJSON(data []byte) error {
+	var zero I
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		p.Item = zero
+		return nil
+	}
+
+	envelope := new(polyJSON)
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return fmt.Errorf("unmarshal envelope: %w", err)
+	}
+
+	item, err := p.registry().Make(envelope.Type)
+	if err != nil {
+		return fmt.Errorf("make item: %w", err)
+	}
+
+	if len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, item); err != nil {
+			return fmt.Errorf("unmarshal item %s: %w", envelope.Type, err)
+		}
+	}
+
+	if p.Item, err = convertTo[I](envelope.Type, item); err != nil {
+		return fmt.Errorf("convert item: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Poly[I]) registry() Registry {
+	if p.Registry == nil {
+		return singleton
+	}
+	return p.Registry
+}
+
+// isNilItem returns true if the item is nil or a nil pointer.
+func isNilItem(item interface{}) bool {
+	if item == nil {
+		return true
+	}
+	value := reflect.ValueOf(item)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
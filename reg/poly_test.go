@@ -0,0 +1,128 @@
+package reg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type polyTestSuite struct {
+	suite.Suite
+	previous Registry
+}
+
+func (suite *polyTestSuite) SetupTest() {
+	suite.previous = Singleton()
+	SetSingleton(NewRegistry())
+	suite.Require().NoError(AddAlias("typeUtils", &Alpha{}))
+	suite.Require().NoError(Register(&Alpha{}))
+	suite.Require().NoError(Register(&Bravo{}))
+}
+
+func (suite *polyTestSuite) TearDownTest() {
+	SetSingleton(suite.previous)
+}
+
+func TestPolySuite(t *testing.T) {
+	suite.Run(t, new(polyTestSuite))
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+type polyHolder struct {
+	Single Poly[Stuff]
+	Many   []Poly[Stuff]
+	Ptr    *Poly[Stuff]
+}
+
+func (suite *polyTestSuite) TestMarshal() {
+	data, err := json.Marshal(&Poly[Stuff]{Item: &Alpha{Name: "Hubert", Number: 17.23}})
+	suite.Require().NoError(err)
+	suite.Assert().JSONEq(`{"type":"[typeUtils]Alpha","data":{"Name":"Hubert","Number":17.23}}`, string(data))
+	data, err = json.Marshal(Poly[Stuff]{})
+	suite.Require().NoError(err)
+	suite.Assert().Equal("null", string(data))
+	data, err = json.Marshal(Poly[*Alpha]{})
+	suite.Require().NoError(err)
+	suite.Assert().Equal("null", string(data))
+}
+
+func (suite *polyTestSuite) TestMarshalUnregistered() {
+	_, err := json.Marshal(NewPoly[Stuff](&Alpha{}, NewRegistry()))
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "no registration")
+}
+
+func (suite *polyTestSuite) TestCycle() {
+	holder := &polyHolder{
+		Single: Poly[Stuff]{Item: &Alpha{Name: "Hubert", Number: 17.23}},
+		Many: []Poly[Stuff]{
+			{Item: &Bravo{Finished: true, Iterations: 79}},
+			{Item: &Alpha{Name: "Wilbur"}},
+			{},
+		},
+	}
+	data, err := json.Marshal(holder)
+	suite.Require().NoError(err)
+	suite.Assert().Contains(string(data), `"type":"[typeUtils]Alpha"`)
+	suite.Assert().Contains(string(data), `"type":"[typeUtils]Bravo"`)
+
+	result := new(polyHolder)
+	suite.Require().NoError(json.Unmarshal(data, result))
+	suite.Assert().Equal(holder, result)
+}
+
+func (suite *polyTestSuite) TestUnmarshalErrors() {
+	poly := new(Poly[Stuff])
+	err := json.Unmarshal([]byte(`{"type":"[typeUtils]Charlie","data":{}}`), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+	err = json.Unmarshal([]byte(`{"type":"[typeUtils]Alpha","data":{"Name":7}}`), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "unmarshal item")
+	bravo := new(Poly[*Bravo])
+	err = json.Unmarshal([]byte(`{"type":"[typeUtils]Alpha","data":{}}`), bravo)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "convert item")
+}
+
+func (suite *polyTestSuite) TestLocalRegistry() {
+	registry := NewRegistry()
+	suite.Require().NoError(registry.AddAlias("local", &Alpha{}))
+	suite.Require().NoError(registry.Register(&Alpha{}))
+	data, err := json.Marshal(NewPoly[Stuff](&Alpha{Name: "Local"}, registry))
+	suite.Require().NoError(err)
+	suite.Assert().Contains(string(data), `"type":"[local]Alpha"`)
+	poly := NewPoly[Stuff](nil, registry)
+	suite.Require().NoError(json.Unmarshal(data, poly))
+	suite.Assert().Equal(&Alpha{Name: "Local"}, poly.Item)
+}
+
+func (suite *polyTestSuite) TestBuiltins() {
+	// Values of unregistered types can't be marshaled.
+	_, err := json.Marshal(&Poly[interface{}]{Item: 42})
+	suite.Assert().ErrorIs(err, &ErrNotRegistered{})
+
+	registry := NewRegistry(WithDerivedNames())
+	data, err := json.Marshal(&Poly[int]{Item: 42, Registry: registry})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`{"type":"int","data":42}`, string(data))
+	number := &Poly[int]{Registry: registry}
+	suite.Require().NoError(json.Unmarshal(data, number))
+	suite.Assert().Equal(42, number.Item)
+
+	data, err = json.Marshal(&Poly[[]string]{Item: []string{"a", "b"}, Registry: registry})
+	suite.Require().NoError(err)
+	list := &Poly[[]string]{Registry: registry}
+	suite.Require().NoError(json.Unmarshal(data, list))
+	suite.Assert().Equal([]string{"a", "b"}, list.Item)
+
+	// Items are created via Make so interface items are read back as pointers.
+	data, err = json.Marshal(&Poly[interface{}]{Item: 42, Registry: registry})
+	suite.Require().NoError(err)
+	item := &Poly[interface{}]{Registry: registry}
+	suite.Require().NoError(json.Unmarshal(data, item))
+	expected := 42
+	suite.Assert().Equal(&expected, item.Item)
+}
@@ -0,0 +1,102 @@
+package reg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Make sure the interfaces are satisfied at compile time.
+var _ xml.Marshaler = Poly[interface{}]{}
+var _ xml.Unmarshaler = &Poly[interface{}]{}
+
+// XMLSchemaInstance is the XML Schema instance namespace for the xsi:type attribute.
+const XMLSchemaInstance = "http://www.w3.org/2001/XMLSchema-instance"
+
+const (
+	xsiPrefix = "xsi"
+	xsiType   = "type"
+
+	xmlPolyElement = "Poly"
+)
+
+// MarshalXML marshals the item into an XML element with an xsi:type attribute
+// containing its type name:
+//
+//	<Single xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="[typeUtils]Alpha">
+//	  <Name>Hubert</Name>
+//	  <Number>17.23</Number>
+//	</Single>
+//
+// A nil item is not marshaled at all.
+// A top-level Poly is marshaled into an element named Poly.
+// Slice and array items (other than byte slices and arrays) are not supported
+// since encoding/xml marshals them as sequences of elements.
+func (p Poly[I]) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	if isNilItem(p.Item) {
+		return nil
+	}
+
+	name, err := p.registry().NameFor(p.Item)
+	if err != nil {
+		return fmt.Errorf("get name for item: %w", err)
+	}
+
+	if itemType := reflect.Indirect(reflect.ValueOf(p.Item)).Type(); isXMLSequence(itemType) {
+		return fmt.Errorf("item %s is a sequence of XML elements", name)
+	}
+
+	// The default element name for a top-level Poly is the generic type name,
+	// which is not a valid XML name.
+	if strings.ContainsRune(start.Name.Local, '[') {
+		start.Name.Local = xmlPolyElement
+	}
+
+	// The encoding/xml package makes up its own prefixes for namespaced attributes,
+	// so the xsi prefix is declared and used explicitly.
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:" + xsiPrefix}, Value: XMLSchemaInstance},
+		xml.Attr{Name: xml.Name{Local: xsiPrefix + ":" + xsiType}, Value: name})
+	if err := encoder.EncodeElement(p.Item, start); err != nil {
+		return fmt.Errorf("encode item %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// UnmarshalXML unmarshals an XML element into a new item of the type named by its xsi:type attribute.
+func (p *Poly[I]) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	name := ""
+	for _, attr := range start.Attr {
+		if attr.Name.Local == xsiType && (attr.Name.Space == XMLSchemaInstance || attr.Name.Space == xsiPrefix) {
+			name = attr.Value
+			break
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("no xsi:type attribute on element %s", start.Name.Local)
+	}
+
+	item, err := p.registry().Make(name)
+	if err != nil {
+		return fmt.Errorf("make item for xsi:type '%s' on element %s: %w", name, start.Name.Local, err)
+	}
+
+	if err := decoder.DecodeElement(item, &start); err != nil {
+		return fmt.Errorf("decode item %s: %w", name, err)
+	}
+
+	if p.Item, err = convertTo[I](name, item); err != nil {
+		return fmt.Errorf("convert item: %w", err)
+	}
+
+	return nil
+}
+
+// isXMLSequence returns true if encoding/xml marshals values of the type
+// as a sequence of elements instead of a single element.
+func isXMLSequence(itemType reflect.Type) bool {
+	kind := itemType.Kind()
+	return (kind == reflect.Slice || kind == reflect.Array) && itemType.Elem().Kind() != reflect.Uint8
+}
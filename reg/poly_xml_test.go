@@ -0,0 +1,65 @@
+package reg
+
+import (
+	"encoding/xml"
+)
+
+type polyXMLHolder struct {
+	XMLName xml.Name `xml:"holder"`
+	Single  Poly[Stuff]
+	Many    []Poly[Stuff] `xml:"many>item"`
+	Ptr     *Poly[Stuff]
+}
+
+func (suite *polyTestSuite) TestMarshalXML() {
+	data, err := xml.Marshal(&Poly[Stuff]{Item: &Alpha{Name: "Hubert", Number: 17.23}})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`<Poly xmlns:xsi="`+XMLSchemaInstance+`" xsi:type="[typeUtils]Alpha">`+
+		`<Name>Hubert</Name><Number>17.23</Number></Poly>`, string(data))
+	data, err = xml.Marshal(&polyXMLHolder{})
+	suite.Require().NoError(err)
+	suite.Assert().Equal(`<holder><many></many></holder>`, string(data))
+}
+
+func (suite *polyTestSuite) TestCycleXML() {
+	holder := &polyXMLHolder{
+		XMLName: xml.Name{Local: "holder"},
+		Single:  Poly[Stuff]{Item: &Alpha{Name: "Hubert", Number: 17.23}},
+		Many: []Poly[Stuff]{
+			{Item: &Bravo{Finished: true, Iterations: 79}},
+			{Item: &Alpha{Name: "Wilbur"}},
+		},
+	}
+	data, err := xml.Marshal(holder)
+	suite.Require().NoError(err)
+	suite.Assert().Contains(string(data), `<Single xmlns:xsi="`+XMLSchemaInstance+`" xsi:type="[typeUtils]Alpha">`)
+	suite.Assert().Contains(string(data), `<item xmlns:xsi="`+XMLSchemaInstance+`" xsi:type="[typeUtils]Bravo">`)
+
+	result := new(polyXMLHolder)
+	suite.Require().NoError(xml.Unmarshal(data, result))
+	suite.Assert().Equal(holder, result)
+}
+
+func (suite *polyTestSuite) TestUnmarshalXMLUndeclaredPrefix() {
+	poly := new(Poly[Stuff])
+	suite.Require().NoError(xml.Unmarshal([]byte(`<item xsi:type="[typeUtils]Bravo"><Iterations>3</Iterations></item>`), poly))
+	suite.Assert().Equal(&Bravo{Iterations: 3}, poly.Item)
+}
+
+func (suite *polyTestSuite) TestUnmarshalXMLErrors() {
+	poly := new(Poly[Stuff])
+	err := xml.Unmarshal([]byte(`<item><Name>Hubert</Name></item>`), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "no xsi:type attribute on element item")
+	err = xml.Unmarshal([]byte(`<item xsi:type="[typeUtils]Charlie"></item>`), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "make item for xsi:type '[typeUtils]Charlie' on element item")
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+	err = xml.Unmarshal([]byte(`<item xsi:type="[typeUtils]Alpha"><Number>x</Number></item>`), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "decode item")
+	bravo := new(Poly[*Bravo])
+	err = xml.Unmarshal([]byte(`<item xsi:type="[typeUtils]Alpha"></item>`), bravo)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "convert item")
+}
@@ -0,0 +1,91 @@
+package reg
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Make sure the interfaces are satisfied at compile time.
+var _ yaml.Marshaler = Poly[interface{}]{}
+var _ yaml.Unmarshaler = &Poly[interface{}]{}
+
+// yamlNullTag is the standard YAML tag for null values.
+const yamlNullTag = "!!null"
+
+// MarshalYAML marshals the item into a YAML node tagged with its type name.
+// The tag is written in verbatim form so the type name is not subject to tag handle expansion:
+//
+//	item: !<[typeUtils]Alpha>
+//	  name: Hubert
+//	  number: 17.23
+//
+// A nil item is marshaled as YAML null.
+func (p Poly[I]) MarshalYAML() (interface{}, error) {
+	if isNilItem(p.Item) {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: yamlNullTag, Value: "null"}, nil
+	}
+
+	name, err := p.registry().NameFor(p.Item)
+	if err != nil {
+		return nil, fmt.Errorf("get name for item: %w", err)
+	}
+
+	node := new(yaml.Node)
+	if err := node.Encode(p.Item); err != nil {
+		return nil, fmt.Errorf("encode item %s: %w", name, err)
+	}
+
+	// The yaml.v3 encoder writes tags that don't start with '!' in verbatim form.
+	node.Tag = name
+	return node, nil
+}
+
+// UnmarshalYAML unmarshals a YAML node into a new item of the type named by the node tag.
+// Both the verbatim (!<[typeUtils]Alpha>) and shorthand (![typeUtils]Alpha) tag forms are accepted.
+func (p *Poly[I]) UnmarshalYAML(node *yaml.Node) error {
+	var zero I
+	if node.Tag == yamlNullTag {
+		p.Item = zero
+		return nil
+	}
+
+	name := yamlTagName(node.Tag)
+	if name == "" {
+		return fmt.Errorf("no type name tag on YAML node at line %d", node.Line)
+	}
+
+	item, err := p.registry().Make(name)
+	if err != nil {
+		return fmt.Errorf("make item: %w", err)
+	}
+
+	if err := node.Decode(item); err != nil {
+		return fmt.Errorf("decode item %s: %w", name, err)
+	}
+
+	if p.Item, err = convertTo[I](name, item); err != nil {
+		return fmt.Errorf("convert item: %w", err)
+	}
+
+	return nil
+}
+
+// yamlTagName returns the type name from a YAML tag or an empty string
+// if the tag is missing or is a standard YAML tag (e.g. !!map).
+func yamlTagName(tag string) string {
+	if tag == "" || strings.HasPrefix(tag, "!!") {
+		return ""
+	}
+
+	// The yaml.v3 parser removes the '!<' and '>' from verbatim tags.
+	// Escaped verbatim tags (!%3C...%3E) written as local tags by other encoders
+	// come back from the parser as '!<...>' so handle those as well.
+	tag = strings.TrimPrefix(tag, "!")
+	if strings.HasPrefix(tag, "<") && strings.HasSuffix(tag, ">") {
+		tag = tag[1 : len(tag)-1]
+	}
+
+	return tag
+}
@@ -0,0 +1,63 @@
+package reg
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+func (suite *polyTestSuite) TestMarshalYAML() {
+	data, err := yaml.Marshal(&Poly[Stuff]{Item: &Alpha{Name: "Hubert", Number: 17.23}})
+	suite.Require().NoError(err)
+	suite.Assert().Equal("!<[typeUtils]Alpha>\nname: Hubert\nnumber: 17.23\n", string(data))
+	data, err = yaml.Marshal(Poly[Stuff]{})
+	suite.Require().NoError(err)
+	suite.Assert().Equal("null\n", string(data))
+}
+
+func (suite *polyTestSuite) TestCycleYAML() {
+	holder := &polyHolder{
+		Single: Poly[Stuff]{Item: &Alpha{Name: "Hubert", Number: 17.23}},
+		Many: []Poly[Stuff]{
+			{Item: &Bravo{Finished: true, Iterations: 79}},
+			{Item: &Alpha{Name: "Wilbur"}},
+		},
+	}
+	data, err := yaml.Marshal(holder)
+	suite.Require().NoError(err)
+	suite.Assert().Contains(string(data), "single: !<[typeUtils]Alpha>\n")
+	suite.Assert().Contains(string(data), "- !<[typeUtils]Bravo>\n")
+
+	result := new(polyHolder)
+	suite.Require().NoError(yaml.Unmarshal(data, result))
+	suite.Assert().Equal(holder, result)
+}
+
+func (suite *polyTestSuite) TestUnmarshalYAMLVerbatim() {
+	result := new(polyHolder)
+	suite.Require().NoError(yaml.Unmarshal([]byte(`
+single: !<[typeUtils]Bravo>
+  iterations: 3
+many:
+  - !%3C[typeUtils]Alpha%3E
+    name: Wilbur
+`), result))
+	suite.Assert().Equal(&Bravo{Iterations: 3}, result.Single.Item)
+	suite.Require().Len(result.Many, 1)
+	suite.Assert().Equal(&Alpha{Name: "Wilbur"}, result.Many[0].Item)
+}
+
+func (suite *polyTestSuite) TestUnmarshalYAMLErrors() {
+	poly := new(Poly[Stuff])
+	err := yaml.Unmarshal([]byte("name: Hubert\n"), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "no type name tag")
+	err = yaml.Unmarshal([]byte("![typeUtils]Charlie\nname: Hubert\n"), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+	err = yaml.Unmarshal([]byte("![typeUtils]Alpha\nnumber: [1]\n"), poly)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "decode item")
+	bravo := new(Poly[*Bravo])
+	err = yaml.Unmarshal([]byte("![typeUtils]Alpha\nname: Hubert\n"), bravo)
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "convert item")
+}
@@ -1,6 +1,7 @@
 package reg
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -100,3 +101,44 @@ type Bravo struct {
 func (b *Bravo) Info() string {
 	return fmt.Sprintf("%t: %d", b.Finished, b.Iterations)
 }
+
+type Charlie struct {
+	Lookup map[string]int
+	Limit  int
+}
+
+type Delta struct {
+	Lookup map[string]int
+	Fail   bool
+}
+
+var errDeltaInit = errors.New("delta init failed")
+
+func (d *Delta) Init() error {
+	if d.Fail {
+		return errDeltaInit
+	}
+	d.Lookup = make(map[string]int)
+	return nil
+}
+
+type failingInit struct{}
+
+func (f *failingInit) Init() error {
+	return errDeltaInit
+}
+
+// Foxtrot provides its own registry name.
+type Foxtrot struct {
+	Value int
+}
+
+func (f Foxtrot) RegistryName() string {
+	return "foxtrot"
+}
+
+type Golf struct{}
+
+func (g *Golf) RegistryName() string {
+	return ""
+}
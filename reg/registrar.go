@@ -1,13 +1,16 @@
 package reg
 
-import "sync"
+import (
+	"reflect"
+	"sync"
+)
 
 // NewRegistrar creates a new Registrar object of the default internal type.
 // Registries created via this function are mutex locked for concurrent access.
 // This is probably redundant and is vigorously untested.
-func NewRegistrar() Registry {
+func NewRegistrar(options ...Option) Registry {
 	return &registrar{
-		Registry: NewRegistry(),
+		Registry: NewRegistry(options...),
 	}
 }
 
@@ -20,14 +23,28 @@ type registrar struct {
 	lock sync.Mutex
 }
 
-// Alias creates an alias to be used to shorten names.
-// Use an empty string to remove a previous alias.
+// AddAlias creates an alias to be used to shorten names.
+// The alias applies to types registered before or after it is created.
 func (reg *registrar) AddAlias(alias string, example interface{}) error {
 	reg.lock.Lock()
 	defer reg.lock.Unlock()
 	return reg.Registry.AddAlias(alias, example)
 }
 
+// RemoveAlias removes an alias and the aliased names created from it.
+func (reg *registrar) RemoveAlias(alias string, keepNames bool) error {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return removeAlias(reg.Registry, alias, keepNames)
+}
+
+// ReplaceAlias redefines an existing alias to refer to the package of the example object.
+func (reg *registrar) ReplaceAlias(alias string, example interface{}, keepNames bool) error {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return replaceAlias(reg.Registry, alias, example, keepNames)
+}
+
 // Register a type by providing an example object.
 func (reg *registrar) Register(example interface{}) error {
 	reg.lock.Lock()
@@ -35,12 +52,68 @@ func (reg *registrar) Register(example interface{}) error {
 	return reg.Registry.Register(example)
 }
 
+// RegisterAs registers a type by providing an explicit name and an example object.
+func (reg *registrar) RegisterAs(name string, example interface{}) error {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return registerAs(reg.Registry, name, example)
+}
+
+// RegisterFactory registers a factory function used by Make to create instances of a type.
+// The factory is called without holding the lock so it may use the registry.
+func (reg *registrar) RegisterFactory(example interface{}, factory func() interface{}) error {
+	inner, ok := reg.Registry.(*registry)
+	if !ok {
+		reg.lock.Lock()
+		defer reg.lock.Unlock()
+		return registerFactory(reg.Registry, example, factory)
+	}
+
+	reg.lock.Lock()
+	exType, err := inner.factoryType(example, factory)
+	reg.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := checkFactoryResult(exType, factory()); err != nil {
+		return err
+	}
+
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return inner.setFactory(example, exType, factory)
+}
+
+// RegisterPrototype registers a prototype object copied by Make to create new instances.
+func (reg *registrar) RegisterPrototype(name string, prototype interface{}) error {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return registerPrototype(reg.Registry, name, prototype)
+}
+
 // Make creates a new instance of the example object with the specified name.
-// The new instance will be created with fields filled with zero values.
+// The new instance will be created with fields filled with zero values
+// (or default values, see WithDefaults)
+// unless a factory function or prototype has been registered for the type.
+// Zero value instances that implement Initializer will be initialized.
+// Factories and Init methods are called without holding the lock so they may use the registry.
 func (reg *registrar) Make(name string) (interface{}, error) {
+	inner, ok := reg.Registry.(*registry)
+	if !ok {
+		reg.lock.Lock()
+		defer reg.lock.Unlock()
+		return reg.Registry.Make(name)
+	}
+
 	reg.lock.Lock()
-	defer reg.lock.Unlock()
-	return reg.Registry.Make(name)
+	maker, err := inner.maker(name)
+	reg.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return maker()
 }
 
 // NameFor returns a name for the specified object.
@@ -49,3 +122,28 @@ func (reg *registrar) NameFor(item interface{}) (string, error) {
 	defer reg.lock.Unlock()
 	return reg.Registry.NameFor(item)
 }
+
+// TypeFor returns the registered type with the specified name.
+func (reg *registrar) TypeFor(name string) (reflect.Type, error) {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return typeFor(reg.Registry, name)
+}
+
+func (reg *registrar) findType(name string) (reflect.Type, error) {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return findType(reg.Registry, name)
+}
+
+// Names returns the current names of all registered types in sorted order.
+func (reg *registrar) Names() []string {
+	names, _ := reg.names()
+	return names
+}
+
+func (reg *registrar) names() ([]string, error) {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	return namesOf(reg.Registry)
+}
@@ -0,0 +1,70 @@
+package reg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Kilo has a field created via the same Registry when it is initialized.
+type Kilo struct {
+	Alpha *Alpha
+}
+
+var kiloRegistry Registry
+
+func (k *Kilo) Init() error {
+	item, err := kiloRegistry.Make(packageName + "/Alpha")
+	if err != nil {
+		return err
+	}
+	k.Alpha = item.(*Alpha)
+	return nil
+}
+
+// Lima has a field created via the same Registry by its factory.
+type Lima struct {
+	Alpha *Alpha
+}
+
+func TestRegistrarReentrant(t *testing.T) {
+	registry := NewRegistrar()
+	kiloRegistry = registry
+	defer func() { kiloRegistry = nil }()
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Kilo{}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// Factories may create other objects via the registry.
+		assert.NoError(t, registry.(FactoryRegisterer).RegisterFactory(&Lima{}, func() interface{} {
+			item, err := registry.Make(packageName + "/Alpha")
+			if err != nil {
+				return nil
+			}
+			return &Lima{Alpha: item.(*Alpha)}
+		}))
+		item, err := registry.Make(packageName + "/Lima")
+		assert.NoError(t, err)
+		if assert.IsType(t, &Lima{}, item) {
+			assert.NotNil(t, item.(*Lima).Alpha)
+		}
+
+		// Init methods may create other objects via the registry.
+		item, err = registry.Make(packageName + "/Kilo")
+		assert.NoError(t, err)
+		if assert.IsType(t, &Kilo{}, item) {
+			assert.NotNil(t, item.(*Kilo).Alpha)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock in registrar")
+	}
+}
@@ -1,9 +1,9 @@
 package reg
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -18,15 +18,19 @@ import (
 // invoke the predefined global object.
 type Registry interface {
 	// AddAlias creates an alias to be used to shorten names.
-	// The alias must exist prior to registering applicable types.
+	// The alias applies to types registered before or after it is created.
 	// Redefining a pre-existing alias is an error.
 	AddAlias(alias string, example interface{}) error
 
 	// Register a type by providing an example object.
+	// Types that implement Namer are registered using the name they provide.
 	Register(example interface{}) error
 
 	// Make creates a new instance of the example object with the specified name.
-	// The new instance will be created with fields filled with zero values.
+	// The new instance will be created with fields filled with zero values
+	// (or default values, see WithDefaults)
+	// unless a factory function or prototype has been registered for the type.
+	// Zero value instances that implement Initializer will be initialized.
 	Make(name string) (interface{}, error)
 
 	// NameFor returns the current name for the registered type of the specified object.
@@ -37,20 +41,132 @@ type Registry interface {
 	Clear()
 }
 
+// Initializer is implemented by types that require initialization after creation.
+// Registry.Make calls Init on new zero value instances of types that implement it.
+// Instances created by factory functions are not initialized via this interface.
+type Initializer interface {
+	Init() error
+}
+
+// Namer is implemented by types that provide their own registry names.
+// Register uses the result of RegistryName as the current name for the type
+// instead of the name generated from the package path.
+// The generated full name and any aliased names are also registered for lookup.
+// RegistryName is called on a new zero value instance of the type
+// and should return a constant non-empty string.
+type Namer interface {
+	RegistryName() string
+}
+
+// TypeFinder is implemented by Registry objects that return the registered type for a name
+// without creating an instance of the type.
+// All Registry objects provided by this package implement it.
+type TypeFinder interface {
+	// TypeFor returns the registered type with the specified name.
+	TypeFor(name string) (reflect.Type, error)
+}
+
+// NameLister is implemented by Registry objects that list the names of their registered types.
+// All Registry objects provided by this package implement it.
+type NameLister interface {
+	// Names returns the current names of all registered types in sorted order.
+	Names() []string
+}
+
+// AliasRemover is implemented by Registry objects that remove and redefine aliases.
+// All Registry objects provided by this package implement it.
+type AliasRemover interface {
+	// RemoveAlias removes an alias and the aliased names created from it.
+	// If keepNames is true the aliased names can still be used to look up types
+	// but will no longer be chosen as current names.
+	RemoveAlias(alias string, keepNames bool) error
+
+	// ReplaceAlias redefines an existing alias to refer to the package of the example object.
+	// The aliased names created from the previous definition are removed as with RemoveAlias.
+	ReplaceAlias(alias string, example interface{}, keepNames bool) error
+}
+
+// ExplicitRegisterer is implemented by Registry objects that register types with explicit names.
+// All Registry objects provided by this package implement it.
+type ExplicitRegisterer interface {
+	// RegisterAs registers a type by providing an explicit name and an example object.
+	// The explicit name becomes the current name for the type,
+	// which keeps names stable when package paths change.
+	// The generated full name and any aliased names are also registered for lookup.
+	// Unexported types may be registered with explicit names.
+	RegisterAs(name string, example interface{}) error
+}
+
+// FactoryRegisterer is implemented by Registry objects that create instances via factory functions.
+// All Registry objects provided by this package implement it.
+type FactoryRegisterer interface {
+	// RegisterFactory registers a factory function used by Make to create instances of a type.
+	// The type is specified by either an example object or the name of a registered type.
+	// An example object must be of a type that is not already registered.
+	// The factory must return a pointer to an instance of the type.
+	RegisterFactory(example interface{}, factory func() interface{}) error
+}
+
+// PrototypeRegisterer is implemented by Registry objects that create instances by copying prototypes.
+// All Registry objects provided by this package implement it.
+type PrototypeRegisterer interface {
+	// RegisterPrototype registers a prototype object copied by Make to create new instances.
+	// If the name is empty the prototype is used for the type of the prototype,
+	// which is registered if necessary.
+	// Otherwise the name is a preset name for which Make returns copies of the prototype.
+	// Preset names are in addition to the names of the type, which is registered if necessary.
+	// Unexported fields can't be copied deeply so prototypes with non-nil maps, slices,
+	// or pointers in unexported fields are rejected, use RegisterFactory for those types.
+	RegisterPrototype(name string, prototype interface{}) error
+}
+
+// Option configures a Registry object created by NewRegistry or NewRegistrar.
+type Option func(reg *registry)
+
 // NewRegistry creates a new Registry object of the default internal type.
-func NewRegistry() Registry {
-	return &registry{
+func NewRegistry(options ...Option) Registry {
+	reg := &registry{
 		aliases: make(map[string]string),
 		byName:  make(map[string]*registration),
 		byType:  make(map[reflect.Type]*registration),
 	}
+	for _, option := range options {
+		option(reg)
+	}
+	return reg
+}
+
+// WithMainPath configures a Registry to use the specified path instead of main
+// as the package path in names of types declared in package main.
+// By default the name of a type Config declared in package main is main/Config.
+// Programs that share serialized data may use a path such as mytool
+// so that the name is mytool/Config instead.
+func WithMainPath(path string) Option {
+	return func(reg *registry) {
+		reg.mainPath = path
+	}
+}
+
+// WithPrivateTypes configures a Registry to allow registration of unexported types.
+// By default unexported types can only be registered with explicit names via RegisterAs.
+// This supports packages that keep implementations of exported interfaces unexported.
+func WithPrivateTypes() Option {
+	return func(reg *registry) {
+		reg.private = true
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////
 //////////////////////////////////////////////////////////////////////////
 
-// Make sure the interface is satisfied at compile time.
+// Make sure the interfaces are satisfied at compile time.
 var _ Registry = &registry{}
+var _ TypeFinder = &registry{}
+var _ NameLister = &registry{}
+var _ AliasRemover = &registry{}
+var _ ExplicitRegisterer = &registry{}
+var _ FactoryRegisterer = &registry{}
+var _ PrototypeRegisterer = &registry{}
 
 // Default Registry implementation.
 type registry struct {
@@ -63,6 +179,22 @@ type registry struct {
 
 	// alias maps shortened 'alias' strings to path prefix to shorten names.
 	aliases map[string]string
+
+	// defaults is true if new instances are filled from default tags (see WithDefaults).
+	defaults bool
+
+	// policies choose current names for registered types (see WithNamePolicy).
+	policies []NamePolicy
+
+	// mainPath replaces the package path of types in package main if not empty (see WithMainPath).
+	mainPath string
+
+	// private is true if unexported types may be registered (see WithPrivateTypes).
+	private bool
+
+	// derivedNames is true if NameFor returns names of composite and predeclared types
+	// (see WithDerivedNames).
+	derivedNames bool
 }
 
 // Registration structure groups data from indexes.
@@ -70,47 +202,188 @@ type registration struct {
 	// currentName includes package path and type name.
 	currentName string
 
-	// allNames is the set of all possible type names (i.e. including aliased).
+	// allNames is the set of all possible type names (i.e. including explicit and aliased).
+	// The first is the explicit name if there is one, otherwise the full name.
 	// The best one will always be in currentName.
 	allNames []string
 
+	// explicit is true if the current name was specified (see RegisterAs and Namer)
+	// and will not be replaced by aliased names.
+	explicit bool
+
 	// typeObj is the reflect.Type object for the example object.
 	typeObj reflect.Type
+
+	// factory creates new instances of the type if not nil.
+	factory func() interface{}
+
+	// defaults for fields of new instances (see WithDefaults).
+	defaults []fieldDefault
+
+	// prototype is copied to create new instances if valid.
+	prototype reflect.Value
+
+	// typeArgs are the type arguments of an instantiated generic type.
+	typeArgs []reflect.Type
+
+	// suffix contains the names of the type arguments at the end of generated names.
+	suffix string
 }
 
 //////////////////////////////////////////////////////////////////////////
 
 // AddAlias creates an alias to be used to shorten names.
-// The alias must exist prior to registering applicable types.
+// The alias applies to types registered before or after it is created.
+// Previously registered types from the package of the example object
+// get an additional aliased name and their current names are chosen again.
 // Redefining a pre-existing alias is an error.
 func (reg *registry) AddAlias(alias string, example interface{}) error {
-	if _, found := reg.aliases[alias]; found {
-		return fmt.Errorf("can't redefine alias %s", alias)
+	if path, found := reg.aliases[alias]; found {
+		return &ErrAliasExists{Alias: alias, Path: path}
+	}
+
+	pkgPath, err := aliasPath(alias, example)
+	if err != nil {
+		return err
+	}
+
+	return reg.addAlias(alias, reg.packagePath(pkgPath))
+}
+
+// RemoveAlias removes an alias and the aliased names created from it.
+// Current names of the affected types are chosen again.
+// If keepNames is true the aliased names can still be used to look up types,
+// which may be useful while migrating stored data to new names.
+// Kept names are removed by Clear.
+func (reg *registry) RemoveAlias(alias string, keepNames bool) error {
+	if _, found := reg.aliases[alias]; !found {
+		return &ErrNoAlias{Alias: alias}
+	}
+
+	delete(reg.aliases, alias)
+	prefix := "[" + alias + "]"
+	for _, item := range reg.byType {
+		names := item.allNames[:1]
+		for _, name := range item.allNames[1:] {
+			if strings.HasPrefix(name, prefix) {
+				if !keepNames {
+					delete(reg.byName, name)
+				}
+			} else {
+				names = append(names, name)
+			}
+		}
+		item.allNames = names
+		reg.chooseCurrentName(item)
+	}
+	reg.renameGenerics(keepNames)
+
+	return nil
+}
+
+// ReplaceAlias redefines an existing alias to refer to the package of the example object.
+// The aliased names created from the previous definition are removed as with RemoveAlias.
+// If the new definition can't be applied the previous definition is restored.
+func (reg *registry) ReplaceAlias(alias string, example interface{}, keepNames bool) error {
+	oldPath, found := reg.aliases[alias]
+	if !found {
+		return &ErrNoAlias{Alias: alias}
+	}
+
+	pkgPath, err := aliasPath(alias, example)
+	if err != nil {
+		return err
+	}
+
+	if err := reg.RemoveAlias(alias, keepNames); err != nil {
+		return err
+	}
+
+	if err := reg.addAlias(alias, reg.packagePath(pkgPath)); err != nil {
+		// Restore the previous definition, which can't conflict with its own names.
+		if restoreErr := reg.addAlias(alias, oldPath); restoreErr != nil {
+			return fmt.Errorf("restore alias %s after '%v': %w", alias, err, restoreErr)
+		}
+		return err
 	}
 
+	return nil
+}
+
+// aliasPath returns the package path of the example object for the specified alias.
+func aliasPath(alias string, example interface{}) (string, error) {
 	exampleType := reflect.TypeOf(example)
 	if exampleType == nil {
-		return fmt.Errorf("find type for alias %s (%v)", alias, example)
+		return "", fmt.Errorf("find type for alias %s (%v)", alias, example)
 	}
 
 	if exampleType.Kind() == reflect.Ptr {
 		exampleType = exampleType.Elem()
 		if exampleType == nil {
-			return fmt.Errorf("no elem type for alias %s (%v)", alias, example)
+			return "", fmt.Errorf("no elem type for alias %s (%v)", alias, example)
 		}
 	}
 
 	pkgPath := exampleType.PkgPath()
 	if pkgPath == "" {
-		return fmt.Errorf("no package path for alias %s (%v)", alias, example)
+		return "", &ErrNoPackagePath{Alias: alias, Type: exampleType}
+	}
+
+	return pkgPath, nil
+}
+
+// addAlias defines the alias for the package path and adds aliased names to registered types.
+func (reg *registry) addAlias(alias, pkgPath string) error {
+	// Find aliased names for previously registered types before changing anything.
+	updates := make(map[*registration]string)
+	for _, item := range reg.byType {
+		fullName, err := reg.genNameFromType(item.typeObj)
+		if err != nil {
+			return fmt.Errorf("getting type name of %v: %w", item.typeObj, err)
+		}
+		if aliased, ok := applyAlias(alias, pkgPath, fullName); ok {
+			// Names kept by RemoveAlias may be reused by the same type.
+			if other, found := reg.byName[aliased]; found && other != item {
+				return &ErrDuplicateName{Name: aliased, Type: other.typeObj}
+			}
+			updates[item] = aliased
+		}
 	}
 
 	reg.aliases[alias] = pkgPath
+	for item, aliased := range updates {
+		item.allNames = append(item.allNames, aliased)
+		reg.byName[aliased] = item
+		reg.chooseCurrentName(item)
+	}
+	reg.renameGenerics(false)
+
 	return nil
 }
 
 // Register a type by providing an example object.
+// Types that implement Namer are registered using the name they provide.
 func (reg *registry) Register(example interface{}) error {
+	return reg.register("", example)
+}
+
+// RegisterAs registers a type by providing an explicit name and an example object.
+// The explicit name becomes the current name for the type,
+// overriding any name provided by the type via Namer.
+// The generated full name and any aliased names are also registered for lookup.
+// Unexported types may be registered with explicit names.
+func (reg *registry) RegisterAs(name string, example interface{}) error {
+	if name == "" {
+		return fmt.Errorf("empty name for %v", example)
+	}
+
+	return reg.register(name, example)
+}
+
+// register a type by providing an example object.
+// If the explicit name is not empty it becomes the current name for the type,
+// otherwise the name provided by a type that implements Namer is used.
+func (reg *registry) register(explicit string, example interface{}) error {
 	// Get reflected type for example object.
 	exType := reflect.TypeOf(example)
 	if exType != nil && exType.Kind() == reflect.Ptr {
@@ -121,8 +394,8 @@ func (reg *registry) Register(example interface{}) error {
 	}
 
 	// Check for previous record.
-	if _, ok := reg.byType[exType]; ok {
-		return fmt.Errorf("previous registration for type %v", exType)
+	if previous, ok := reg.byType[exType]; ok {
+		return &ErrDuplicateType{Name: previous.currentName, Type: exType}
 	}
 
 	// Get type name without any pointer asterisks.
@@ -131,35 +404,87 @@ func (reg *registry) Register(example interface{}) error {
 		typeName = strings.TrimLeft(typeName, "*")
 	}
 
-	typeNameSplit := strings.Split(typeName, ".")
+	// Ignore type arguments of generic types.
+	baseName := typeName
+	if open := strings.Index(baseName, "["); open >= 0 {
+		baseName = baseName[:open]
+	}
+
+	typeNameSplit := strings.Split(baseName, ".")
 	r, _ := utf8.DecodeRuneInString(typeNameSplit[len(typeNameSplit)-1])
-	if !unicode.IsUpper(r) {
-		return fmt.Errorf("type '%s' is private", typeName)
+	if !unicode.IsUpper(r) && !reg.private && explicit == "" {
+		return &ErrPrivateType{Name: typeName, Type: exType}
+	}
+
+	// Get name provided by the type itself.
+	if explicit == "" {
+		if namer, ok := reflect.New(exType).Interface().(Namer); ok {
+			if explicit = namer.RegistryName(); explicit == "" {
+				return fmt.Errorf("empty RegistryName() for type %s", typeName)
+			}
+		}
+	}
+	if explicit != "" && isDerivedName(explicit) {
+		return fmt.Errorf("name '%s' is reserved for composite and predeclared types", explicit)
 	}
 
 	// Create registration record for this type.
 	item := &registration{
 		currentName: typeName,
-		allNames:    make([]string, 1, len(reg.aliases)+1),
+		allNames:    make([]string, 0, len(reg.aliases)+2),
 		typeObj:     exType,
 	}
 
+	if reg.defaults {
+		var err error
+		if item.defaults, err = parseDefaults(exType); err != nil {
+			return fmt.Errorf("parse default tags for %s: %w", typeName, err)
+		}
+	}
+
 	// Initialize default name to full name with package and type.
+	fullName, err := reg.genNameFromInterface(example)
+	if err != nil {
+		return fmt.Errorf("getting type name of example: %w", err)
+	}
 	name, aliases, err := reg.genNames(example, true)
 	if err != nil {
 		return fmt.Errorf("getting type name of example: %w", err)
 	}
 
 	item.currentName = name
-	item.allNames[0] = name
-	for _, alias := range aliases {
-		item.allNames = append(item.allNames, alias)
+	if explicit != "" {
+		item.currentName = explicit
+		item.allNames = append(item.allNames, explicit)
+		item.explicit = true
+	}
+	item.allNames = append(item.allNames, fullName)
+	item.allNames = append(item.allNames, aliases...)
+
+	// Keep type arguments of generic types to rename them if type argument names change.
+	if item.typeArgs, err = reg.typeArgs(exType); err != nil {
+		return fmt.Errorf("getting type arguments of example: %w", err)
+	}
+	if len(item.typeArgs) > 0 {
+		if item.suffix, err = reg.typeArgsSuffix(item.typeArgs); err != nil {
+			return fmt.Errorf("getting type arguments of example: %w", err)
+		}
 	}
 
-	// Add name lookups for all default and aliased names.
-	reg.byName[name] = item
+	// Check for names already used by other registrations.
 	for _, name := range item.allNames {
-		reg.byName[name] = item
+		if other, found := reg.byName[name]; found {
+			return &ErrDuplicateName{Name: name, Type: other.typeObj}
+		}
+	}
+
+	// Add name lookups for explicit and aliased names.
+	// The full name is only added for explicit registrations or if it is the current name,
+	// otherwise it is added if it becomes the current name (see chooseCurrentName).
+	for _, name := range item.allNames {
+		if name != fullName || item.explicit || name == item.currentName {
+			reg.byName[name] = item
+		}
 	}
 
 	// Add type lookup.
@@ -168,13 +493,153 @@ func (reg *registry) Register(example interface{}) error {
 	return nil
 }
 
-var errItemIsNil = errors.New("item is nil")
+// RegisterFactory registers a factory function used by Make to create instances of a type.
+// The type is specified by either an example object or the name of a registered type.
+// An example object must be of a type that is not already registered.
+// The factory must return a pointer to an instance of the type.
+// The factory is invoked once during registration to check the type it returns.
+// The factory replaces any previously registered factory or prototype.
+func (reg *registry) RegisterFactory(example interface{}, factory func() interface{}) error {
+	exType, err := reg.factoryType(example, factory)
+	if err != nil {
+		return err
+	}
+
+	// Check factory result type.
+	if err := checkFactoryResult(exType, factory()); err != nil {
+		return err
+	}
+
+	return reg.setFactory(example, exType, factory)
+}
+
+// factoryType returns the type of the example object or named registration for RegisterFactory.
+func (reg *registry) factoryType(example interface{}, factory func() interface{}) (reflect.Type, error) {
+	if factory == nil {
+		return nil, fmt.Errorf("no factory function for %v", example)
+	}
+
+	if name, byName := example.(string); byName {
+		item, found := reg.byName[name]
+		if !found {
+			return nil, &ErrNotRegistered{Name: name}
+		}
+		return item.typeObj, nil
+	}
+
+	exType := typeOfExample(example)
+	if exType == nil {
+		return nil, fmt.Errorf("no reflected type for %v", example)
+	}
+
+	return exType, nil
+}
+
+// setFactory sets the factory for the example object or named registration
+// after the result of the factory has been checked.
+// An example object of a type that is not registered is registered.
+func (reg *registry) setFactory(example interface{}, exType reflect.Type, factory func() interface{}) error {
+	var item *registration
+	if name, byName := example.(string); byName {
+		var found bool
+		if item, found = reg.byName[name]; !found || item.typeObj != exType {
+			return &ErrNotRegistered{Name: name}
+		}
+	} else {
+		if err := reg.Register(example); err != nil {
+			return err
+		}
+		item = reg.byType[exType]
+	}
+
+	item.factory = factory
+	item.prototype = reflect.Value{}
+	return nil
+}
+
+// checkFactoryResult returns an error if the result of a factory function is not a pointer to the specified type.
+func checkFactoryResult(itemType reflect.Type, result interface{}) error {
+	if result == nil {
+		return fmt.Errorf("factory for %v returns nil", itemType)
+	}
+
+	resultType := reflect.TypeOf(result)
+	if resultType != reflect.PtrTo(itemType) {
+		return fmt.Errorf("factory for %v returns %v", itemType, resultType)
+	}
+
+	if reflect.ValueOf(result).IsNil() {
+		return fmt.Errorf("factory for %v returns nil pointer", itemType)
+	}
+
+	return nil
+}
+
+// RegisterPrototype registers a prototype object copied by Make to create new instances.
+// If the name is empty the prototype is used for the type of the prototype,
+// which is registered if necessary.
+// Otherwise the name is a preset name for which Make returns copies of the prototype.
+// Preset names are in addition to the names of the type, which is registered if necessary.
+// The prototype is copied during registration so later changes to it have no effect.
+// The prototype replaces any previously registered factory or prototype.
+// Unexported fields are copied shallowly so they would be shared by all new instances.
+// Prototypes with non-nil maps, slices, or pointers in unexported fields are rejected.
+func (reg *registry) RegisterPrototype(name string, prototype interface{}) error {
+	value := reflect.ValueOf(prototype)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return fmt.Errorf("nil prototype for %v", value.Type())
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return fmt.Errorf("no reflected type for prototype %v", prototype)
+	}
+	if field := sharedField(value); field != "" {
+		return fmt.Errorf("prototype field %s is unexported and can't be copied", field)
+	}
+
+	if name != "" {
+		if other, found := reg.byName[name]; found {
+			return &ErrDuplicateName{Name: name, Type: other.typeObj}
+		}
+		if isDerivedName(name) {
+			return fmt.Errorf("name '%s' is reserved for composite and predeclared types", name)
+		}
+	}
+
+	if _, found := reg.byType[value.Type()]; !found {
+		if err := reg.Register(prototype); err != nil {
+			return err
+		}
+		// The preset name may be one of the names of the newly registered type.
+		if other, found := reg.byName[name]; name != "" && found {
+			return &ErrDuplicateName{Name: name, Type: other.typeObj}
+		}
+	}
+
+	value = deepCopy(value)
+	if name == "" {
+		item := reg.byType[value.Type()]
+		item.factory = nil
+		item.prototype = value
+	} else {
+		reg.byName[name] = &registration{
+			currentName: name,
+			allNames:    []string{name},
+			typeObj:     value.Type(),
+			prototype:   value,
+		}
+	}
+
+	return nil
+}
 
 // NameFor returns the current name for the registered type of the specified object.
 func (reg *registry) NameFor(item interface{}) (string, error) {
 	itemType := reflect.TypeOf(item)
 	if itemType == nil {
-		return "", errItemIsNil
+		return "", ErrNilItem
 	}
 	if itemType.Kind() == reflect.Ptr {
 		itemType = itemType.Elem()
@@ -182,21 +647,98 @@ func (reg *registry) NameFor(item interface{}) (string, error) {
 
 	registration, ok := reg.byType[itemType]
 	if !ok {
-		return "", fmt.Errorf("no registration for type %s", itemType)
+		if reg.derivedNames {
+			// Composite and predeclared types have names derived from their element types.
+			return reg.derivedName(itemType)
+		}
+		return "", &ErrNotRegistered{Type: itemType}
 	}
 
 	return registration.currentName, nil
 }
 
 // Make creates a new instance of the example object with the specified name.
-// The new instance will be created with fields filled with zero values.
+// The new instance will be created with fields filled with zero values
+// (or default values, see WithDefaults)
+// unless a factory function or prototype has been registered for the type.
+// Zero value instances that implement Initializer will be initialized.
 func (reg *registry) Make(name string) (interface{}, error) {
+	maker, err := reg.maker(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return maker()
+}
+
+// maker returns a function that creates a new instance of the type with the specified name.
+// The function doesn't use the registry so it can be called without holding a lock,
+// which allows factories and Init methods to use the registry (see registrar).
+func (reg *registry) maker(name string) (func() (interface{}, error), error) {
 	item, found := reg.byName[name]
 	if !found {
-		return nil, fmt.Errorf("no registration for type named '%s'", name)
+		// Composite and predeclared types have zero values.
+		itemType, err := reg.derivedType(name)
+		if err != nil {
+			return nil, err
+		}
+		return func() (interface{}, error) {
+			return reflect.New(itemType).Interface(), nil
+		}, nil
+	}
+
+	itemType := item.typeObj
+	if factory := item.factory; factory != nil {
+		return func() (interface{}, error) {
+			result := factory()
+			if err := checkFactoryResult(itemType, result); err != nil {
+				return nil, fmt.Errorf("make %s: %w", name, err)
+			}
+			return result, nil
+		}, nil
 	}
 
-	return reflect.New(item.typeObj).Interface(), nil
+	if prototype := item.prototype; prototype.IsValid() {
+		return func() (interface{}, error) {
+			value := reflect.New(itemType)
+			value.Elem().Set(deepCopy(prototype))
+			return value.Interface(), nil
+		}, nil
+	}
+
+	defaults := item.defaults
+	return func() (interface{}, error) {
+		value := reflect.New(itemType)
+		applyDefaults(value.Elem(), defaults)
+		result := value.Interface()
+		if initializer, ok := result.(Initializer); ok {
+			if err := initializer.Init(); err != nil {
+				return nil, fmt.Errorf("initialize %s: %w", name, err)
+			}
+		}
+		return result, nil
+	}, nil
+}
+
+// TypeFor returns the registered type with the specified name.
+// Unlike Make no instance of the type is created.
+func (reg *registry) TypeFor(name string) (reflect.Type, error) {
+	item, found := reg.byName[name]
+	if !found {
+		return reg.derivedType(name)
+	}
+
+	return item.typeObj, nil
+}
+
+// Names returns the current names of all registered types in sorted order.
+func (reg *registry) Names() []string {
+	names := make([]string, 0, len(reg.byType))
+	for _, registration := range reg.byType {
+		names = append(names, registration.currentName)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func (reg *registry) Clear() {
@@ -207,7 +749,8 @@ func (reg *registry) Clear() {
 
 //////////////////////////////////////////////////////////////////////////
 
-func genNameFromInterface(example interface{}) (string, error) {
+// genNameFromInterface creates the full name with package path and type name for the example object.
+func (reg *registry) genNameFromInterface(example interface{}) (string, error) {
 	itemType := reflect.TypeOf(example)
 	if itemType == nil {
 		return "", fmt.Errorf("no type for item %v", example)
@@ -217,24 +760,42 @@ func genNameFromInterface(example interface{}) (string, error) {
 		itemType = itemType.Elem()
 	}
 
+	return reg.genNameFromType(itemType)
+}
+
+// genNameFromType creates the full name with package path and type name for the specified type.
+// Package paths without slashes (e.g. main or a single word module path) are supported.
+func (reg *registry) genNameFromType(itemType reflect.Type) (string, error) {
 	path := itemType.PkgPath()
 	if path == "" {
-		return "", fmt.Errorf("no path for type %s", itemType.Name())
+		return "", &ErrNoPackagePath{Type: itemType}
 	}
 
-	last := strings.LastIndex(path, "/")
-	if last < 0 {
-		return "", fmt.Errorf("no slash in %s", path)
+	name := itemType.Name()
+	if open := strings.Index(name, "["); open >= 0 {
+		// Replace the type arguments of a generic type with their registry names.
+		typeArgs, err := reg.typeArgs(itemType)
+		if err != nil {
+			return "", err
+		}
+		suffix, err := reg.typeArgsSuffix(typeArgs)
+		if err != nil {
+			return "", err
+		}
+		name = name[:open] + suffix
 	}
 
-	final := path[last:]
-	name := itemType.Name()
+	return reg.packagePath(path) + "/" + name, nil
+}
 
-	if strings.HasPrefix(name, final+".") {
-		name = name[len(final)+1:]
+// packagePath returns the package path used in names for types in the specified package.
+// This is the actual package path except for package main (see WithMainPath).
+func (reg *registry) packagePath(path string) string {
+	if path == "main" && reg.mainPath != "" {
+		return reg.mainPath
 	}
 
-	return path + "/" + name, nil
+	return path
 }
 
 // genNames creates the possible names for the type represented by the example object.
@@ -242,7 +803,7 @@ func genNameFromInterface(example interface{}) (string, error) {
 // If the aliased argument is true a possibly empty array will be returned for the second argument otherwise nil.
 func (reg *registry) genNames(example interface{}, aliased bool) (string, []string, error) {
 	// Initialize default name to full name with package and type.
-	name, err := genNameFromInterface(example)
+	name, err := reg.genNameFromInterface(example)
 	if err != nil {
 		return "", nil, fmt.Errorf("generating basic name: %w", err)
 	}
@@ -253,20 +814,150 @@ func (reg *registry) genNames(example interface{}, aliased bool) (string, []stri
 
 		// Look for any possible aliases for the type and add them to the list of all names.
 		for alias, prefixPath := range reg.aliases {
-			if strings.HasPrefix(name, prefixPath) {
-				aliases = append(aliases, "["+alias+"]"+name[len(prefixPath)+1:])
+			if aliasedName, ok := applyAlias(alias, prefixPath, name); ok {
+				aliases = append(aliases, aliasedName)
 			}
 		}
 
-		// Choose default name again from shortest, therefore most likely an aliased name if there are any.
-		nameLen := len(name)
-		for _, alias := range aliases {
-			// Using <= favors later aliases of same size.
-			if len(alias) <= nameLen {
-				name = alias
-			}
-		}
+		// Choose default name again per the name policy, by default the shortest.
+		sort.Strings(aliases)
+		name = reg.chooseName(typeOfExample(example), append([]string{name}, aliases...))
 	}
 
 	return name, aliases, nil
 }
+
+// applyAlias returns the aliased version of the full name if the alias applies to it.
+// The alias applies to types in the package with the prefix path and its subpackages,
+// so the prefix path must be followed by a slash in the full name.
+// Types in subpackages get aliased names like [alias]sub/pkg/Type.
+func applyAlias(alias, prefixPath, fullName string) (string, bool) {
+	if !strings.HasPrefix(fullName, prefixPath+"/") {
+		return "", false
+	}
+
+	return "[" + alias + "]" + fullName[len(prefixPath)+1:], true
+}
+
+// chooseCurrentName chooses the current name again after the names of the registration change.
+// Explicit names are not replaced.
+func (reg *registry) chooseCurrentName(item *registration) {
+	if !item.explicit {
+		item.currentName = reg.chooseName(item.typeObj, item.allNames)
+		// The full name may not have been added for lookup yet.
+		if _, found := reg.byName[item.currentName]; !found {
+			reg.byName[item.currentName] = item
+		}
+	}
+}
+
+// typeFor returns the registered type with the specified name
+// if the registry implements TypeFinder,
+// otherwise the type of a new instance created by Make.
+func typeFor(registry Registry, name string) (reflect.Type, error) {
+	if finder, ok := registry.(TypeFinder); ok {
+		return finder.TypeFor(name)
+	}
+
+	item, err := registry.Make(name)
+	if err != nil {
+		return nil, err
+	}
+	return typeOfExample(item), nil
+}
+
+// typeWrapper is implemented by Registry wrappers in this package
+// which implement TypeFinder whether or not the Registry they wrap does.
+type typeWrapper interface {
+	// findType returns the registered type with the specified name
+	// or an error if the wrapped Registry doesn't look up types.
+	findType(name string) (reflect.Type, error)
+}
+
+// findType returns the registered type with the specified name without creating an instance of it
+// or an *ErrUnsupported error if the registry doesn't look up types.
+func findType(registry Registry, name string) (reflect.Type, error) {
+	if wrapper, ok := registry.(typeWrapper); ok {
+		return wrapper.findType(name)
+	}
+	if finder, ok := registry.(TypeFinder); ok {
+		return finder.TypeFor(name)
+	}
+
+	return nil, &ErrUnsupported{Method: "TypeFor", Type: reflect.TypeOf(registry)}
+}
+
+// nameWrapper is implemented by Registry wrappers in this package
+// which implement NameLister whether or not the Registry they wrap does.
+type nameWrapper interface {
+	// names returns the current names of all registered types
+	// or an error if the wrapped Registry doesn't list names.
+	names() ([]string, error)
+}
+
+// namesOf returns the current names of all registered types
+// or an *ErrUnsupported error if the registry doesn't list names.
+func namesOf(registry Registry) ([]string, error) {
+	if wrapper, ok := registry.(nameWrapper); ok {
+		return wrapper.names()
+	}
+	if lister, ok := registry.(NameLister); ok {
+		return lister.Names(), nil
+	}
+
+	return nil, &ErrUnsupported{Method: "Names", Type: reflect.TypeOf(registry)}
+}
+
+// removeAlias removes the alias if the registry implements AliasRemover.
+func removeAlias(registry Registry, alias string, keepNames bool) error {
+	if remover, ok := registry.(AliasRemover); ok {
+		return remover.RemoveAlias(alias, keepNames)
+	}
+
+	return &ErrUnsupported{Method: "RemoveAlias", Type: reflect.TypeOf(registry)}
+}
+
+// replaceAlias redefines the alias if the registry implements AliasRemover.
+func replaceAlias(registry Registry, alias string, example interface{}, keepNames bool) error {
+	if remover, ok := registry.(AliasRemover); ok {
+		return remover.ReplaceAlias(alias, example, keepNames)
+	}
+
+	return &ErrUnsupported{Method: "ReplaceAlias", Type: reflect.TypeOf(registry)}
+}
+
+// registerAs registers the type with an explicit name if the registry implements ExplicitRegisterer.
+func registerAs(registry Registry, name string, example interface{}) error {
+	if registerer, ok := registry.(ExplicitRegisterer); ok {
+		return registerer.RegisterAs(name, example)
+	}
+
+	return &ErrUnsupported{Method: "RegisterAs", Type: reflect.TypeOf(registry)}
+}
+
+// registerFactory registers the factory if the registry implements FactoryRegisterer.
+func registerFactory(registry Registry, example interface{}, factory func() interface{}) error {
+	if registerer, ok := registry.(FactoryRegisterer); ok {
+		return registerer.RegisterFactory(example, factory)
+	}
+
+	return &ErrUnsupported{Method: "RegisterFactory", Type: reflect.TypeOf(registry)}
+}
+
+// registerPrototype registers the prototype if the registry implements PrototypeRegisterer.
+func registerPrototype(registry Registry, name string, prototype interface{}) error {
+	if registerer, ok := registry.(PrototypeRegisterer); ok {
+		return registerer.RegisterPrototype(name, prototype)
+	}
+
+	return &ErrUnsupported{Method: "RegisterPrototype", Type: reflect.TypeOf(registry)}
+}
+
+// typeOfExample returns the type of the example object without any pointer.
+func typeOfExample(example interface{}) reflect.Type {
+	exType := reflect.TypeOf(example)
+	if exType != nil && exType.Kind() == reflect.Ptr {
+		exType = exType.Elem()
+	}
+	return exType
+}
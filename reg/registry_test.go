@@ -1,13 +1,18 @@
 package reg
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
+	"os/exec"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -93,6 +98,231 @@ func (suite *registryTestSuite) TestRegister() {
 	suite.Assert().Contains(err.Error(), "is private")
 }
 
+func (suite *registryTestSuite) TestRegisterAs() {
+	suite.Assert().NoError(suite.registry.AddAlias("typeUtils", &Alpha{}))
+	suite.Assert().NoError(suite.reg.RegisterAs("alpha", &Alpha{}))
+	name, err := suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("alpha", name)
+	for _, name := range []string{"alpha", packageName + "/Alpha", "[typeUtils]Alpha"} {
+		item, err := suite.registry.Make(name)
+		suite.Assert().NoError(err)
+		suite.Assert().IsType(&Alpha{}, item)
+	}
+	suite.Assert().Equal([]string{"alpha"}, suite.reg.Names())
+
+	err = suite.reg.RegisterAs("alpha", &Bravo{})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "name 'alpha' already registered for type reg.Alpha")
+	err = suite.reg.RegisterAs(packageName+"/Alpha", &Bravo{})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "already registered for type reg.Alpha")
+	err = suite.reg.RegisterAs("", &Bravo{})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "empty name")
+	err = suite.reg.RegisterAs("other", &Alpha{})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "previous registration for type")
+	suite.Assert().Len(suite.reg.byType, 1)
+	suite.Assert().Len(suite.reg.byName, 3)
+}
+
+func (suite *registryTestSuite) TestRegisterNamer() {
+	suite.Assert().NoError(suite.registry.AddAlias("typeUtils", &Foxtrot{}))
+	suite.Assert().NoError(suite.registry.Register(&Foxtrot{}))
+	name, err := suite.registry.NameFor(Foxtrot{Value: 7})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("foxtrot", name)
+	for _, name := range []string{"foxtrot", packageName + "/Foxtrot", "[typeUtils]Foxtrot"} {
+		item, err := suite.registry.Make(name)
+		suite.Assert().NoError(err)
+		suite.Assert().Equal(&Foxtrot{}, item)
+	}
+
+	err = suite.reg.RegisterAs("foxtrot", &Alpha{})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "name 'foxtrot' already registered for type reg.Foxtrot")
+	err = suite.registry.Register(&Golf{})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "empty RegistryName()")
+
+	// Explicit names override names provided by the type.
+	suite.registry.Clear()
+	suite.Assert().NoError(suite.reg.RegisterAs("other", &Foxtrot{}))
+	name, err = suite.registry.NameFor(&Foxtrot{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("other", name)
+}
+
+func (suite *registryTestSuite) TestRegisterFactory() {
+	factory := func() interface{} {
+		return &Charlie{Lookup: make(map[string]int), Limit: 10}
+	}
+	suite.Assert().NoError(suite.reg.RegisterFactory(&Charlie{}, factory))
+	item, err := suite.registry.Make(packageName + "/Charlie")
+	suite.Assert().NoError(err)
+	suite.Require().IsType(&Charlie{}, item)
+	charlie := item.(*Charlie)
+	suite.Assert().NotNil(charlie.Lookup)
+	suite.Assert().Equal(10, charlie.Limit)
+	name, err := suite.registry.NameFor(charlie)
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(packageName+"/Charlie", name)
+
+	err = suite.reg.RegisterFactory(&Charlie{}, factory)
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "previous registration")
+	err = suite.reg.RegisterFactory(&Alpha{}, nil)
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "no factory function")
+	err = suite.reg.RegisterFactory(&Alpha{}, func() interface{} { return &Bravo{} })
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "factory for reg.Alpha returns *reg.Bravo")
+	err = suite.reg.RegisterFactory(&Alpha{}, func() interface{} { return Alpha{} })
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "factory for reg.Alpha returns reg.Alpha")
+	err = suite.reg.RegisterFactory(&Alpha{}, func() interface{} { return nil })
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "returns nil")
+	err = suite.reg.RegisterFactory(&Alpha{}, func() interface{} { return (*Alpha)(nil) })
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "returns nil pointer")
+	suite.Assert().Len(suite.reg.byType, 1)
+}
+
+func (suite *registryTestSuite) TestRegisterFactoryByName() {
+	err := suite.reg.RegisterFactory(packageName+"/Alpha", func() interface{} { return &Alpha{} })
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	suite.Assert().NoError(suite.reg.RegisterFactory(packageName+"/Alpha", func() interface{} {
+		return &Alpha{Name: "Factory"}
+	}))
+	item, err := suite.registry.Make(packageName + "/Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Alpha{Name: "Factory"}, item)
+
+	// Factory misbehaves after registration.
+	calls := 0
+	suite.Assert().NoError(suite.reg.RegisterFactory(packageName+"/Alpha", func() interface{} {
+		calls++
+		if calls > 1 {
+			return &Bravo{}
+		}
+		return &Alpha{}
+	}))
+	_, err = suite.registry.Make(packageName + "/Alpha")
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "factory for reg.Alpha returns *reg.Bravo")
+}
+
+func (suite *registryTestSuite) TestRegisterPrototype() {
+	prototype := &Charlie{Lookup: map[string]int{"one": 1}, Limit: 17}
+	suite.Assert().NoError(suite.reg.RegisterPrototype("", prototype))
+	prototype.Lookup["two"] = 2
+	item, err := suite.registry.Make(packageName + "/Charlie")
+	suite.Assert().NoError(err)
+	suite.Require().IsType(&Charlie{}, item)
+	charlie := item.(*Charlie)
+	suite.Assert().Equal(&Charlie{Lookup: map[string]int{"one": 1}, Limit: 17}, charlie)
+
+	// Each instance is a separate copy.
+	charlie.Lookup["three"] = 3
+	charlie.Limit = 0
+	item, err = suite.registry.Make(packageName + "/Charlie")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Charlie{Lookup: map[string]int{"one": 1}, Limit: 17}, item)
+
+	// Prototype and factory replace each other.
+	suite.Assert().NoError(suite.reg.RegisterFactory(packageName+"/Charlie", func() interface{} {
+		return &Charlie{Limit: 23}
+	}))
+	item, err = suite.registry.Make(packageName + "/Charlie")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Charlie{Limit: 23}, item)
+	suite.Assert().NoError(suite.reg.RegisterPrototype("", &Charlie{Limit: 29}))
+	item, err = suite.registry.Make(packageName + "/Charlie")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Charlie{Limit: 29}, item)
+
+	err = suite.reg.RegisterPrototype("", (*Charlie)(nil))
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "nil prototype")
+	err = suite.reg.RegisterPrototype("", nil)
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "no reflected type")
+}
+
+func (suite *registryTestSuite) TestRegisterPrototypePresets() {
+	suite.Assert().NoError(suite.reg.RegisterPrototype("small-cache", &Charlie{Limit: 10}))
+	suite.Assert().NoError(suite.reg.RegisterPrototype("large-cache", &Charlie{Limit: 10000}))
+	item, err := suite.registry.Make("small-cache")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Charlie{Limit: 10}, item)
+	item, err = suite.registry.Make("large-cache")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Charlie{Limit: 10000}, item)
+	name, err := suite.registry.NameFor(item)
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(packageName+"/Charlie", name)
+	itemType, err := suite.reg.TypeFor("small-cache")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(reflect.TypeOf(Charlie{}), itemType)
+
+	// The type itself is still created normally.
+	item, err = suite.registry.Make(packageName + "/Charlie")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Charlie{}, item)
+
+	err = suite.reg.RegisterPrototype("small-cache", &Charlie{Limit: 5})
+	suite.Assert().Error(err)
+	suite.Assert().ErrorIs(err, &ErrDuplicateName{Name: "small-cache"})
+	err = suite.reg.RegisterPrototype(packageName+"/Charlie", &Charlie{Limit: 5})
+	suite.Assert().Error(err)
+	suite.Assert().ErrorIs(err, &ErrDuplicateName{Type: reflect.TypeOf(Charlie{})})
+
+	// Names of a type registered by RegisterPrototype are not preset names.
+	suite.Require().NoError(suite.registry.AddAlias("app", &Alpha{}))
+	err = suite.reg.RegisterPrototype("[app]Alpha", &Alpha{Name: "preset"})
+	suite.Assert().ErrorIs(err, &ErrDuplicateName{Name: "[app]Alpha", Type: reflect.TypeOf(Alpha{})})
+	suite.Assert().Same(suite.reg.byType[reflect.TypeOf(Alpha{})], suite.reg.byName["[app]Alpha"])
+	item, err = suite.registry.Make("[app]Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Alpha{}, item)
+	suite.Assert().NoError(suite.reg.RegisterFactory("[app]Alpha", func() interface{} {
+		return &Alpha{Name: "factory"}
+	}))
+	item, err = suite.registry.Make("[app]Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Alpha{Name: "factory"}, item)
+	_, err = suite.registry.Make(packageName + "/Alpha")
+	suite.Assert().ErrorIs(err, &ErrNotRegistered{Name: packageName + "/Alpha"})
+	suite.Assert().NoError(suite.reg.RemoveAlias("app", false))
+	item, err = suite.registry.Make(packageName + "/Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Alpha{Name: "factory"}, item)
+}
+
+// Cache keeps its entries in an unexported field.
+type Cache struct {
+	Limit   int
+	entries map[string]string
+}
+
+func (suite *registryTestSuite) TestRegisterPrototypeUnexported() {
+	// Unexported fields would be shared by all instances.
+	err := suite.reg.RegisterPrototype("small-cache", &Cache{Limit: 10, entries: map[string]string{}})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "prototype field reg.Cache.entries is unexported")
+	_, found := suite.reg.byName["small-cache"]
+	suite.Assert().False(found)
+
+	suite.Assert().NoError(suite.reg.RegisterPrototype("small-cache", &Cache{Limit: 10}))
+	item, err := suite.registry.Make("small-cache")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(&Cache{Limit: 10}, item)
+}
+
 func (suite *registryTestSuite) TestNameFor() {
 	example := &Alpha{}
 	suite.Assert().NoError(suite.registry.Register(example))
@@ -104,7 +334,15 @@ func (suite *registryTestSuite) TestNameFor() {
 func (suite *registryTestSuite) TestNameForNilItem() {
 	exType, err := suite.registry.NameFor(nil)
 	suite.Assert().Equal("", exType)
-	suite.Assert().ErrorIs(err, errItemIsNil)
+	suite.Assert().ErrorIs(err, ErrNilItem)
+}
+
+func (suite *registryTestSuite) TestNames() {
+	suite.Assert().Empty(suite.reg.Names())
+	suite.Assert().NoError(suite.registry.Register(&Bravo{}))
+	suite.Assert().NoError(suite.registry.AddAlias("typeUtils", &Alpha{}))
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	suite.Assert().Equal([]string{"[typeUtils]Alpha", "[typeUtils]Bravo"}, suite.reg.Names())
 }
 
 func (suite *registryTestSuite) TestMake() {
@@ -116,6 +354,117 @@ func (suite *registryTestSuite) TestMake() {
 	suite.Assert().IsType(example, item)
 }
 
+func (suite *registryTestSuite) TestTypeFor() {
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	itemType, err := suite.reg.TypeFor(packageName + "/Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(reflect.TypeOf(Alpha{}), itemType)
+	_, err = suite.reg.TypeFor(packageName + "/Bravo")
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "no registration for type named")
+}
+
+// otherRegistry is a Registry implementation that only provides the Registry methods.
+type otherRegistry struct {
+	Registry
+}
+
+func (suite *registryTestSuite) TestOptionalInterfaces() {
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	for _, registry := range []Registry{
+		suite.registry,
+		NewRegistrar(),
+		NewAlias("x", suite.registry),
+		NewGobRegistry(suite.registry),
+		NewRestricted(suite.registry),
+	} {
+		suite.Assert().Implements((*TypeFinder)(nil), registry)
+		suite.Assert().Implements((*NameLister)(nil), registry)
+		suite.Assert().Implements((*AliasRemover)(nil), registry)
+		suite.Assert().Implements((*ExplicitRegisterer)(nil), registry)
+		suite.Assert().Implements((*FactoryRegisterer)(nil), registry)
+		suite.Assert().Implements((*PrototypeRegisterer)(nil), registry)
+	}
+
+	// Registry implementations without the optional interfaces can still be wrapped.
+	other := &otherRegistry{Registry: suite.registry}
+	otherType := reflect.TypeOf(other)
+	itemType, err := typeFor(other, packageName+"/Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(reflect.TypeOf(Alpha{}), itemType)
+	names, err := namesOf(other)
+	suite.Assert().Nil(names)
+	suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "Names", Type: reflect.TypeOf(other)})
+	item, err := NewRestricted(other, AllowNames(packageName+"/Alpha")).Make(packageName + "/Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().IsType(&Alpha{}, item)
+
+	// Types can't be checked without creating instances of them.
+	created := 0
+	suite.Require().NoError(suite.reg.RegisterFactory(&Bravo{}, func() interface{} {
+		created++
+		return &Bravo{}
+	}))
+	created = 0
+	for _, registry := range []Registry{
+		other, NewAlias("x", other), NewGobRegistry(other), NewRestricted(other, AllowNames(packageName+"/Bravo")),
+	} {
+		restricted := NewRestricted(registry, AllowImplementing[Stuff]())
+		_, err = restricted.Make(packageName + "/Bravo")
+		suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "TypeFor", Type: otherType})
+		_, err = restricted.(TypeFinder).TypeFor(packageName + "/Bravo")
+		suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "TypeFor", Type: otherType})
+	}
+	suite.Assert().Zero(created)
+	suite.Assert().Nil(NewAlias("x", other).Names())
+	for _, registry := range []Registry{other, NewAlias("x", other), NewGobRegistry(other), NewRestricted(other)} {
+		suite.Assert().ErrorIs(RegisterGob(registry), &ErrUnsupported{Method: "Names"})
+	}
+
+	// Optional methods of wrapped registries are reported as unsupported.
+	for _, registry := range []Registry{other, NewAlias("x", other), NewGobRegistry(other), NewRestricted(other)} {
+		err := registerAs(registry, "bravo", &Bravo{})
+		suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "RegisterAs", Type: otherType})
+		err = registerFactory(registry, &Bravo{}, func() interface{} { return &Bravo{} })
+		suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "RegisterFactory", Type: otherType})
+		err = registerPrototype(registry, "", &Bravo{})
+		suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "RegisterPrototype", Type: otherType})
+		err = removeAlias(registry, "x", false)
+		suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "RemoveAlias", Type: otherType})
+		err = replaceAlias(registry, "x", &Bravo{}, false)
+		suite.Assert().ErrorIs(err, &ErrUnsupported{Method: "ReplaceAlias", Type: otherType})
+	}
+	previous := Singleton()
+	defer SetSingleton(previous)
+	SetSingleton(other)
+	suite.Assert().ErrorIs(RegisterAs("bravo", &Bravo{}), &ErrUnsupported{Method: "RegisterAs"})
+	suite.Assert().ErrorIs(RemoveAlias("x", false), &ErrUnsupported{Method: "RemoveAlias"})
+}
+
+func (suite *registryTestSuite) TestMakeInitializer() {
+	suite.Assert().NoError(suite.registry.Register(&Delta{}))
+	item, err := suite.registry.Make(packageName + "/Delta")
+	suite.Assert().NoError(err)
+	suite.Require().IsType(&Delta{}, item)
+	suite.Assert().NotNil(item.(*Delta).Lookup)
+
+	suite.Assert().NoError(suite.reg.RegisterFactory(packageName+"/Delta", func() interface{} {
+		return &Delta{Fail: true}
+	}))
+	item, err = suite.registry.Make(packageName + "/Delta")
+	suite.Assert().NoError(err)
+	suite.Assert().Nil(item.(*Delta).Lookup)
+}
+
+func (suite *registryTestSuite) TestMakeInitializerError() {
+	failing := &registration{typeObj: reflect.TypeOf(failingInit{})}
+	suite.reg.byName["failing"] = failing
+	_, err := suite.registry.Make("failing")
+	suite.Assert().Error(err)
+	suite.Assert().ErrorIs(err, errDeltaInit)
+	suite.Assert().Contains(err.Error(), "initialize failing")
+}
+
 func (suite *registryTestSuite) TestCycleSimple() {
 	example := &Alpha{}
 	suite.Assert().NoError(suite.registry.Register(example))
@@ -147,12 +496,116 @@ func (suite *registryTestSuite) TestCycleAlias() {
 	name, err := suite.registry.NameFor(example)
 	suite.Assert().NoError(err)
 	suite.Assert().Equal("[typeUtils]Alpha", name)
+	itemType, err := suite.reg.TypeFor(name)
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(exType, itemType)
+	_, err = suite.reg.TypeFor(packageName + "/Alpha")
+	suite.Assert().ErrorIs(err, &ErrNotRegistered{Name: packageName + "/Alpha"})
 	object, err := suite.registry.Make(name)
 	suite.Assert().NoError(err)
 	suite.Assert().NotNil(object)
 	suite.Assert().Equal(reflect.TypeOf(example), reflect.TypeOf(object))
 }
 
+func (suite *registryTestSuite) TestAliasAfterRegister() {
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	suite.Assert().NoError(suite.reg.RegisterAs("bravo", &Bravo{}))
+	suite.Assert().NoError(suite.registry.AddAlias("typeUtils", &Alpha{}))
+	name, err := suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("[typeUtils]Alpha", name)
+	name, err = suite.registry.NameFor(&Bravo{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("bravo", name)
+	for _, name := range []string{packageName + "/Alpha", "[typeUtils]Alpha", "[typeUtils]Bravo"} {
+		_, err := suite.registry.Make(name)
+		suite.Assert().NoError(err)
+	}
+	registration := suite.reg.byType[reflect.TypeOf(Alpha{})]
+	suite.Require().NotNil(registration)
+	suite.Assert().Equal([]string{packageName + "/Alpha", "[typeUtils]Alpha"}, registration.allNames)
+
+	// Aliased names may not collide with existing names.
+	suite.registry.Clear()
+	suite.Assert().NoError(suite.reg.RegisterAs("[other]Alpha", &Bravo{}))
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	err = suite.registry.AddAlias("other", &Alpha{})
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "name '[other]Alpha' already registered for type reg.Bravo")
+	suite.Assert().Empty(suite.reg.aliases)
+	name, err = suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(packageName+"/Alpha", name)
+}
+
+func (suite *registryTestSuite) TestRemoveAlias() {
+	suite.Assert().NoError(suite.registry.AddAlias("typeUtils", &Alpha{}))
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	suite.Assert().NoError(suite.reg.RemoveAlias("typeUtils", false))
+	suite.Assert().Empty(suite.reg.aliases)
+	name, err := suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(packageName+"/Alpha", name)
+	_, err = suite.registry.Make("[typeUtils]Alpha")
+	suite.Assert().Error(err)
+	suite.Assert().Len(suite.reg.byName, 1)
+
+	// Keep aliased names for lookup.
+	suite.Assert().NoError(suite.registry.AddAlias("typeUtils", &Alpha{}))
+	suite.Assert().NoError(suite.reg.RemoveAlias("typeUtils", true))
+	name, err = suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(packageName+"/Alpha", name)
+	item, err := suite.registry.Make("[typeUtils]Alpha")
+	suite.Assert().NoError(err)
+	suite.Assert().IsType(&Alpha{}, item)
+
+	// Kept names may be reused by the same type.
+	suite.Assert().NoError(suite.registry.AddAlias("typeUtils", &Alpha{}))
+	name, err = suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("[typeUtils]Alpha", name)
+
+	err = suite.reg.RemoveAlias("unknown", false)
+	suite.Assert().Error(err)
+	suite.Assert().ErrorIs(err, &ErrNoAlias{Alias: "unknown"})
+}
+
+func (suite *registryTestSuite) TestReplaceAlias() {
+	suite.Assert().NoError(suite.registry.AddAlias("x", &Alpha{}))
+	suite.Assert().NoError(suite.registry.Register(&Alpha{}))
+	suite.Assert().NoError(suite.registry.Register(&url.URL{}))
+	suite.Assert().NoError(suite.reg.ReplaceAlias("x", &url.URL{}, true))
+	suite.Assert().Equal("net/url", suite.reg.aliases["x"])
+	name, err := suite.registry.NameFor(&url.URL{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("[x]URL", name)
+	name, err = suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(packageName+"/Alpha", name)
+	_, err = suite.registry.Make("[x]Alpha")
+	suite.Assert().NoError(err)
+
+	err = suite.reg.ReplaceAlias("y", &Alpha{}, false)
+	suite.Assert().Error(err)
+	suite.Assert().ErrorIs(err, &ErrNoAlias{Alias: "y"})
+	err = suite.reg.ReplaceAlias("x", 17, false)
+	suite.Assert().Error(err)
+	suite.Assert().Contains(err.Error(), "no package path")
+	suite.Assert().Equal("net/url", suite.reg.aliases["x"])
+
+	// Previous definition is restored if the new one conflicts with existing names.
+	suite.Assert().NoError(suite.reg.RegisterAs("[z]URL", &Bravo{}))
+	suite.Assert().NoError(suite.registry.AddAlias("z", &Alpha{}))
+	err = suite.reg.ReplaceAlias("z", &url.URL{}, false)
+	suite.Assert().Error(err)
+	suite.Assert().ErrorIs(err, &ErrDuplicateName{Name: "[z]URL", Type: reflect.TypeOf(Bravo{})})
+	suite.Assert().Equal(packageName, suite.reg.aliases["z"])
+	name, err = suite.registry.NameFor(&Alpha{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("[z]Alpha", name)
+}
+
 func (suite *registryTestSuite) TestGenNames() {
 	example := &Alpha{}
 	name, aliases, err := suite.reg.genNames(example, false)
@@ -184,16 +637,76 @@ func (suite *registryTestSuite) TestGenNames() {
 	suite.Assert().Contains(err.Error(), "no path for type")
 }
 
+func (suite *registryTestSuite) TestApplyAlias() {
+	name, ok := applyAlias("app", "example.com/foo", "example.com/foo/X")
+	suite.Assert().True(ok)
+	suite.Assert().Equal("[app]X", name)
+	name, ok = applyAlias("app", "example.com/foo", "example.com/foo/sub/pkg/X")
+	suite.Assert().True(ok)
+	suite.Assert().Equal("[app]sub/pkg/X", name)
+	_, ok = applyAlias("app", "example.com/foo", "example.com/foobar/X")
+	suite.Assert().False(ok)
+	_, ok = applyAlias("app", "example.com/foo", "example.com/fo/X")
+	suite.Assert().False(ok)
+}
+
+func (suite *registryTestSuite) TestAliasSubpackage() {
+	suite.Assert().NoError(suite.registry.AddAlias("enc", (*encoding.TextMarshaler)(nil)))
+	suite.Assert().NoError(suite.registry.Register(&json.Decoder{}))
+	name, err := suite.registry.NameFor(&json.Decoder{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("[enc]json/Decoder", name)
+	item, err := suite.registry.Make(name)
+	suite.Assert().NoError(err)
+	suite.Assert().IsType(&json.Decoder{}, item)
+
+	// Applied to previously registered types as well.
+	suite.Assert().NoError(suite.reg.RemoveAlias("enc", false))
+	suite.Assert().NoError(suite.registry.AddAlias("enc", (*encoding.TextMarshaler)(nil)))
+	name, err = suite.registry.NameFor(&json.Decoder{})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("[enc]json/Decoder", name)
+}
+
+func (suite *registryTestSuite) TestSingleSegmentPath() {
+	suite.Assert().NoError(suite.registry.Register(time.Duration(0)))
+	name, err := suite.registry.NameFor(time.Second)
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("time/Duration", name)
+	item, err := suite.registry.Make(name)
+	suite.Assert().NoError(err)
+	suite.Assert().IsType(new(time.Duration), item)
+	suite.Assert().NoError(suite.registry.AddAlias("t", time.Duration(0)))
+	name, err = suite.registry.NameFor(time.Second)
+	suite.Assert().NoError(err)
+	suite.Assert().Equal("[t]Duration", name)
+}
+
+func (suite *registryTestSuite) TestPackageMain() {
+	if testing.Short() {
+		suite.T().Skip("builds and runs a program")
+	}
+
+	// Types can't be declared in package main within a package test.
+	output, err := exec.Command("go", "run", "./testdata/mainpkg").CombinedOutput()
+	suite.Require().NoError(err, string(output))
+	suite.Assert().Equal(`main/Config *main.Config
+[cli]Config *main.Config
+mytool/Config *main.Config
+[cli]Config *main.Config
+`, string(output))
+}
+
 func (suite *registryTestSuite) TestGenTypeName() {
 	example := &Alpha{}
-	name, err := genNameFromInterface(example)
+	name, err := suite.reg.genNameFromInterface(example)
 	suite.Assert().NoError(err)
 	suite.Assert().Equal(packageName+"/Alpha", name)
 
-	_, err = genNameFromInterface(&example)
+	_, err = suite.reg.genNameFromInterface(&example)
 	suite.Assert().Error(err)
 	suite.Assert().Contains(err.Error(), "no path for type")
-	_, err = genNameFromInterface(1)
+	_, err = suite.reg.genNameFromInterface(1)
 	suite.Assert().Error(err)
 	suite.Assert().Contains(err.Error(), "no path for type")
 }
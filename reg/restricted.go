@@ -0,0 +1,243 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// ErrRestricted is returned by the Make method of a restricted Registry
+// when the specified name is not allowed by its restrictions.
+type ErrRestricted struct {
+	// Name is the type name passed to Make.
+	Name string
+
+	// Type is the registered type for the name
+	// or nil if the name was rejected without looking up the type.
+	Type reflect.Type
+}
+
+func (e *ErrRestricted) Error() string {
+	return fmt.Sprintf("type named '%s' is not allowed", e.Name)
+}
+
+// Restriction specifies names or types that may be created by a restricted Registry.
+// Restrictions are created by AllowNames, AllowPattern, AllowImplementing, and AllOf.
+// The zero Restriction allows nothing.
+type Restriction struct {
+	// name returns true if the name is allowed or is nil if the name is not checked.
+	name func(name string) bool
+
+	// item returns true if the registered type is allowed or is nil if the type is not checked.
+	item func(itemType reflect.Type) bool
+}
+
+// NewRestricted returns a Registry that limits the types that can be created via Make
+// or returned by TypeFor.
+// A name is allowed if any of the restrictions allow it.
+// Use AllOf to require multiple restrictions to be met.
+// Names that are not allowed return an *ErrRestricted error
+// without creating an instance of the type.
+// Names are checked before types so the type for a name is only looked up
+// if some restriction might allow it.
+// Restrictions that check types (e.g. AllowImplementing) require the specified Registry
+// to look up types via TypeFinder, otherwise names that must be checked
+// return an *ErrUnsupported error.
+// All other methods are passed through to the specified Registry.
+//
+// A restricted Registry is intended for use when type names come from untrusted input,
+// for example an HTTP request body.
+// If the registry is nil the Singleton Registry will be used.
+func NewRestricted(registry Registry, restrictions ...Restriction) Registry {
+	if registry == nil {
+		registry = singleton
+	}
+	return &restricted{
+		Registry:     registry,
+		restrictions: restrictions,
+	}
+}
+
+// AllowNames returns a Restriction that allows the specified names.
+func AllowNames(names ...string) Restriction {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+	return Restriction{
+		name: func(name string) bool {
+			return allowed[name]
+		},
+	}
+}
+
+// AllowPattern returns a Restriction that allows names that match the specified pattern.
+// Use ^ and $ in the pattern to match the entire name.
+func AllowPattern(pattern *regexp.Regexp) Restriction {
+	return Restriction{
+		name: pattern.MatchString,
+	}
+}
+
+// AllowImplementing returns a Restriction that allows types which implement
+// the interface specified by the type parameter.
+// Either the registered type or a pointer to it must implement the interface.
+func AllowImplementing[I any]() Restriction {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("AllowImplementing type %v is not an interface", iface))
+	}
+	return Restriction{
+		item: func(itemType reflect.Type) bool {
+			return itemType.Implements(iface) || reflect.PtrTo(itemType).Implements(iface)
+		},
+	}
+}
+
+// AllOf returns a Restriction that allows names that are allowed by all the specified restrictions.
+func AllOf(restrictions ...Restriction) Restriction {
+	var all Restriction
+	for _, restriction := range restrictions {
+		all.name = bothNames(all.name, restriction.name)
+		all.item = bothTypes(all.item, restriction.item)
+	}
+	return all
+}
+
+// bothNames returns a name check that requires both checks, either of which may be nil.
+func bothNames(first, second func(name string) bool) func(name string) bool {
+	if first == nil || second == nil {
+		if first == nil {
+			return second
+		}
+		return first
+	}
+	return func(name string) bool {
+		return first(name) && second(name)
+	}
+}
+
+// bothTypes returns a type check that requires both checks, either of which may be nil.
+func bothTypes(first, second func(itemType reflect.Type) bool) func(itemType reflect.Type) bool {
+	if first == nil || second == nil {
+		if first == nil {
+			return second
+		}
+		return first
+	}
+	return func(itemType reflect.Type) bool {
+		return first(itemType) && second(itemType)
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////
+
+// Restricted Registry implementation.
+type restricted struct {
+	Registry
+	restrictions []Restriction
+}
+
+// Make creates a new instance of the example object with the specified name.
+// The name must be allowed by at least one restriction.
+func (reg *restricted) Make(name string) (interface{}, error) {
+	if _, err := reg.check(name, nil); err != nil {
+		return nil, err
+	}
+
+	return reg.Registry.Make(name)
+}
+
+// RemoveAlias removes an alias and the aliased names created from it.
+func (reg *restricted) RemoveAlias(alias string, keepNames bool) error {
+	return removeAlias(reg.Registry, alias, keepNames)
+}
+
+// ReplaceAlias redefines an existing alias to refer to the package of the example object.
+func (reg *restricted) ReplaceAlias(alias string, example interface{}, keepNames bool) error {
+	return replaceAlias(reg.Registry, alias, example, keepNames)
+}
+
+// RegisterAs registers a type by providing an explicit name and an example object.
+// Registration is not restricted.
+func (reg *restricted) RegisterAs(name string, example interface{}) error {
+	return registerAs(reg.Registry, name, example)
+}
+
+// RegisterFactory registers a factory function used by Make to create instances of a type.
+// Registration is not restricted.
+func (reg *restricted) RegisterFactory(example interface{}, factory func() interface{}) error {
+	return registerFactory(reg.Registry, example, factory)
+}
+
+// RegisterPrototype registers a prototype object copied by Make to create new instances.
+// Registration is not restricted.
+func (reg *restricted) RegisterPrototype(name string, prototype interface{}) error {
+	return registerPrototype(reg.Registry, name, prototype)
+}
+
+// Names returns the current names of all registered types in sorted order.
+// Names are not restricted.
+// Returns nil if the wrapped Registry doesn't list names.
+func (reg *restricted) Names() []string {
+	names, _ := reg.names()
+	return names
+}
+
+func (reg *restricted) names() ([]string, error) {
+	return namesOf(reg.Registry)
+}
+
+// TypeFor returns the registered type with the specified name.
+// The name must be allowed by at least one restriction.
+// The type is only looked up if a restriction allows the name or has to check the type.
+func (reg *restricted) TypeFor(name string) (reflect.Type, error) {
+	return reg.check(name, typeFor)
+}
+
+func (reg *restricted) findType(name string) (reflect.Type, error) {
+	return reg.check(name, findType)
+}
+
+// check returns an error if the name is not allowed by at least one restriction.
+// Types that must be checked are looked up without creating an instance of the type,
+// which requires the wrapped Registry to implement TypeFinder.
+// The type for a name allowed without checking its type is looked up via lookup
+// or nil is returned if lookup is nil.
+func (reg *restricted) check(name string, lookup func(registry Registry, name string) (reflect.Type, error)) (reflect.Type, error) {
+	var checkType []Restriction
+	allowed := false
+	for _, restriction := range reg.restrictions {
+		if restriction.name == nil && restriction.item == nil {
+			continue
+		} else if restriction.name != nil && !restriction.name(name) {
+			continue
+		}
+		if restriction.item == nil {
+			allowed = true
+			break
+		}
+		checkType = append(checkType, restriction)
+	}
+	if allowed {
+		if lookup == nil {
+			return nil, nil
+		}
+		return lookup(reg.Registry, name)
+	} else if len(checkType) == 0 {
+		return nil, &ErrRestricted{Name: name}
+	}
+
+	itemType, err := findType(reg.Registry, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, restriction := range checkType {
+		if restriction.item(itemType) {
+			return itemType, nil
+		}
+	}
+
+	return nil, &ErrRestricted{Name: name, Type: itemType}
+}
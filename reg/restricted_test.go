@@ -0,0 +1,111 @@
+package reg
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func restrictedRegistry(t *testing.T) Registry {
+	registry := NewRegistry()
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Bravo{}))
+	require.NoError(t, registry.Register(&Example1{}))
+	return registry
+}
+
+func assertRestricted(t *testing.T, registry Registry, name string) {
+	item, err := registry.Make(name)
+	require.Error(t, err)
+	assert.Nil(t, item)
+	var restricted *ErrRestricted
+	require.True(t, errors.As(err, &restricted))
+	assert.Equal(t, name, restricted.Name)
+	assert.Equal(t, "type named '"+name+"' is not allowed", err.Error())
+	_, err = registry.(TypeFinder).TypeFor(name)
+	assert.ErrorAs(t, err, &restricted)
+}
+
+func TestRestrictedNames(t *testing.T) {
+	registry := NewRestricted(restrictedRegistry(t), AllowNames("[app]Alpha"))
+	item, err := registry.Make("[app]Alpha")
+	require.NoError(t, err)
+	assert.IsType(t, &Alpha{}, item)
+	assertRestricted(t, registry, "[app]Bravo")
+	// Names that are not allowed are rejected before they are looked up.
+	assertRestricted(t, registry, "[app]Charlie")
+	registry = NewRestricted(restrictedRegistry(t), AllowNames("[app]Charlie"))
+	_, err = registry.Make("[app]Charlie")
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: "[app]Charlie"})
+	name, err := registry.NameFor(&Bravo{})
+	require.NoError(t, err)
+	assert.Equal(t, "[app]Bravo", name)
+}
+
+func TestRestrictedPattern(t *testing.T) {
+	registry := NewRestricted(restrictedRegistry(t), AllowPattern(regexp.MustCompile(`^\[app\]Example\d$`)))
+	_, err := registry.Make("[app]Example1")
+	require.NoError(t, err)
+	assertRestricted(t, registry, "[app]Alpha")
+}
+
+func TestRestrictedImplementing(t *testing.T) {
+	registry := NewRestricted(restrictedRegistry(t), AllowImplementing[Stuff]())
+	stuff, err := MakeAs[Stuff](registry, "[app]Bravo")
+	require.NoError(t, err)
+	assert.IsType(t, &Bravo{}, stuff)
+	assertRestricted(t, registry, "[app]Example1")
+	assert.Panics(t, func() { AllowImplementing[Alpha]() })
+
+	registry = NewRestricted(restrictedRegistry(t), AllowImplementing[fmt.Stringer](), AllowNames("[app]Example1"))
+	_, err = registry.Make("[app]Example1")
+	require.NoError(t, err)
+	assertRestricted(t, registry, "[app]Alpha")
+}
+
+func TestRestrictedAllOf(t *testing.T) {
+	registry := NewRestricted(restrictedRegistry(t),
+		AllOf(AllowImplementing[Stuff](), AllowPattern(regexp.MustCompile(`Alpha$`))))
+	_, err := registry.Make("[app]Alpha")
+	require.NoError(t, err)
+	assertRestricted(t, registry, "[app]Bravo")
+	assertRestricted(t, registry, "[app]Example1")
+
+	// No restrictions at all allows nothing.
+	assertRestricted(t, NewRestricted(restrictedRegistry(t)), "[app]Alpha")
+}
+
+func TestRestrictedHostileName(t *testing.T) {
+	const hostile = "[9223372036854775807]int"
+	registry := NewRestricted(restrictedRegistry(t), AllowPattern(regexp.MustCompile(`^\[app\]`)))
+	_, err := registry.Make(hostile)
+	var restricted *ErrRestricted
+	require.True(t, errors.As(err, &restricted))
+	assert.Nil(t, restricted.Type)
+	_, err = registry.(TypeFinder).TypeFor(hostile)
+	assert.ErrorAs(t, err, &restricted)
+
+	// Names allowed by a restriction are still limited by the registry.
+	registry = NewRestricted(restrictedRegistry(t), AllowPattern(regexp.MustCompile(`.`)))
+	_, err = registry.Make(hostile)
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: hostile})
+	registry = NewRestricted(restrictedRegistry(t), AllowImplementing[Stuff]())
+	_, err = registry.Make(hostile)
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: hostile})
+	deep := strings.Repeat("*", 100000) + "int"
+	_, err = registry.Make(deep)
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: deep})
+	deep = strings.Repeat("[]", maxNestingDepth+1) + "int"
+	_, err = registry.Make(deep)
+	assert.ErrorIs(t, err, &ErrNotRegistered{Name: deep})
+	registry = NewRestricted(restrictedRegistry(t), AllOf(AllowImplementing[Stuff](), AllowNames("[app]Bravo")))
+	_, err = registry.Make(hostile)
+	require.True(t, errors.As(err, &restricted))
+	assert.Nil(t, restricted.Type)
+}
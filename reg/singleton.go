@@ -1,5 +1,7 @@
 package reg
 
+import "reflect"
+
 var singleton = NewRegistry()
 
 // Singleton returns the global Registry object created during initialization.
@@ -51,7 +53,43 @@ func NameFor(item interface{}) (string, error) {
 	return singleton.NameFor(item)
 }
 
+// Names invokes reg.Singleton().Names().
+func Names() []string {
+	names, _ := namesOf(singleton)
+	return names
+}
+
 // Register invokes reg.Singleton().Register().
 func Register(example interface{}) error {
 	return singleton.Register(example)
 }
+
+// RegisterAs invokes reg.Singleton().RegisterAs().
+func RegisterAs(name string, example interface{}) error {
+	return registerAs(singleton, name, example)
+}
+
+// RegisterFactory invokes reg.Singleton().RegisterFactory().
+func RegisterFactory(example interface{}, factory func() interface{}) error {
+	return registerFactory(singleton, example, factory)
+}
+
+// RegisterPrototype invokes reg.Singleton().RegisterPrototype().
+func RegisterPrototype(name string, prototype interface{}) error {
+	return registerPrototype(singleton, name, prototype)
+}
+
+// RemoveAlias invokes reg.Singleton().RemoveAlias().
+func RemoveAlias(alias string, keepNames bool) error {
+	return removeAlias(singleton, alias, keepNames)
+}
+
+// ReplaceAlias invokes reg.Singleton().ReplaceAlias().
+func ReplaceAlias(alias string, example interface{}, keepNames bool) error {
+	return replaceAlias(singleton, alias, example, keepNames)
+}
+
+// TypeFor invokes reg.Singleton().TypeFor().
+func TypeFor(name string) (reflect.Type, error) {
+	return typeFor(singleton, name)
+}
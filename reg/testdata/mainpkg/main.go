@@ -0,0 +1,44 @@
+// Program mainpkg registers a type declared in package main for TestPackageMain.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/madkins23/go-type/reg"
+)
+
+type Config struct {
+	Name string
+}
+
+func main() {
+	for _, registry := range []reg.Registry{reg.NewRegistry(), reg.NewRegistry(reg.WithMainPath("mytool"))} {
+		if err := registry.Register(&Config{}); err != nil {
+			fail(err)
+		}
+		fullName, err := registry.NameFor(&Config{})
+		if err != nil {
+			fail(err)
+		}
+		if err := registry.AddAlias("cli", &Config{}); err != nil {
+			fail(err)
+		}
+		aliasedName, err := registry.NameFor(&Config{})
+		if err != nil {
+			fail(err)
+		}
+		for _, name := range []string{fullName, aliasedName} {
+			item, err := registry.Make(name)
+			if err != nil {
+				fail(err)
+			}
+			fmt.Printf("%s %T\n", name, item)
+		}
+	}
+}
+
+func fail(err error) {
+	fmt.Println(err)
+	os.Exit(1)
+}
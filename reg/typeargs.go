@@ -0,0 +1,204 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Instantiations of generic types are registered like any other type.
+// Their names are the name of the generic type followed by the names of the type arguments
+// in brackets, where the type arguments are named via the registry:
+//
+//	[app]Box[[app]Alpha]
+//	[app]Pair[string,map[string][app]Alpha]
+//
+// Type arguments must be registered types or composite or predeclared types built from them.
+// Names of instantiations are updated when the names of their type arguments change.
+// Make accepts any combination of the registered names of the generic type and its type arguments.
+
+// typeArgs returns the type arguments of an instantiated generic type or nil for other types.
+// The reflect package doesn't provide type arguments so they are parsed from the type name,
+// which contains their package paths (e.g. Box[example.com/pkg.Alpha]).
+func (reg *registry) typeArgs(itemType reflect.Type) ([]reflect.Type, error) {
+	name := itemType.Name()
+	open := strings.Index(name, "[")
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return nil, nil
+	}
+
+	args := splitTypeArgs(name[open+1 : len(name)-1])
+	typeArgs := make([]reflect.Type, len(args))
+	for i, arg := range args {
+		argType, err := reg.qualifiedType(arg)
+		if err != nil {
+			return nil, fmt.Errorf("type argument of %s: %w", name, err)
+		}
+		typeArgs[i] = argType
+	}
+
+	return typeArgs, nil
+}
+
+// typeArgsSuffix returns the bracketed registry names of the type arguments.
+func (reg *registry) typeArgsSuffix(typeArgs []reflect.Type) (string, error) {
+	names := make([]string, len(typeArgs))
+	for i, argType := range typeArgs {
+		name, err := reg.elementName(argType)
+		if err != nil {
+			return "", fmt.Errorf("type argument: %w", err)
+		}
+		names[i] = name
+	}
+
+	return "[" + strings.Join(names, ",") + "]", nil
+}
+
+// qualifiedType returns the type for a type name in the format used by the reflect package
+// for type arguments, for example []*example.com/pkg.Alpha.
+func (reg *registry) qualifiedType(name string) (reflect.Type, error) {
+	// Names from the reflect package are not limited in depth.
+	qualified := func(name string, _ int) (reflect.Type, error) {
+		return reg.qualifiedType(name)
+	}
+	if itemType, err := parseComposite(name, 0, qualified); itemType != nil || err != nil {
+		return itemType, err
+	}
+
+	for itemType := range reg.byType {
+		if itemType.PkgPath()+"."+itemType.Name() == name {
+			return itemType, nil
+		}
+	}
+
+	return nil, &ErrNotRegistered{Name: name}
+}
+
+// genericType returns the registered instantiation of a generic type for the specified name
+// nested at the specified depth.
+// Returns nil and no error if the name is not the name of a generic type instantiation.
+func (reg *registry) genericType(name string, depth int) (reflect.Type, error) {
+	if !strings.HasSuffix(name, "]") {
+		return nil, nil
+	}
+	open := openingBracket(name)
+	if open <= 0 {
+		return nil, nil
+	}
+
+	base := name[:open] + "["
+	args := splitTypeArgs(name[open+1 : len(name)-1])
+	typeArgs := make([]reflect.Type, len(args))
+	for i, arg := range args {
+		argType, err := reg.elementType(arg, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("type argument: %w", err)
+		}
+		typeArgs[i] = argType
+	}
+
+	for _, item := range reg.byType {
+		if sameTypes(item.typeArgs, typeArgs) {
+			for _, itemName := range item.allNames {
+				if strings.HasPrefix(itemName, base) && reg.byName[itemName] == item {
+					return item.typeObj, nil
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// renameGenerics updates the names of instantiated generic types
+// after the names of their type arguments have changed.
+// Previous names are removed unless keepNames is true (see RemoveAlias).
+// Combinations of names that are still registered can be used with Make as usual.
+func (reg *registry) renameGenerics(keepNames bool) {
+	// Repeat until there are no changes as type arguments may themselves be generic.
+	for changed := true; changed; {
+		changed = false
+		for _, item := range reg.byType {
+			if len(item.typeArgs) == 0 {
+				continue
+			}
+			suffix, err := reg.typeArgsSuffix(item.typeArgs)
+			if err != nil || suffix == item.suffix {
+				continue
+			}
+
+			for i, name := range item.allNames {
+				if i == 0 && item.explicit {
+					continue
+				}
+				renamed := strings.TrimSuffix(name, item.suffix) + suffix
+				if other, found := reg.byName[renamed]; !found || other == item {
+					// Full names are not always used for lookup (see register).
+					if reg.byName[name] == item {
+						if !keepNames {
+							delete(reg.byName, name)
+						}
+						reg.byName[renamed] = item
+					}
+					item.allNames[i] = renamed
+				}
+			}
+			item.suffix = suffix
+			reg.chooseCurrentName(item)
+			changed = true
+		}
+	}
+}
+
+// splitTypeArgs splits a list of type arguments at commas that are not nested in brackets.
+func splitTypeArgs(list string) []string {
+	var args []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(list); i++ {
+		switch list[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(args, strings.TrimSpace(list[start:]))
+}
+
+// openingBracket returns the index of the bracket that opens the final bracket of the name
+// or -1 if there is none.
+func openingBracket(name string) int {
+	depth := 0
+	for i := len(name) - 1; i >= 0; i-- {
+		switch name[i] {
+		case ']':
+			depth++
+		case '[':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// sameTypes returns true if the lists contain the same types in the same order.
+func sameTypes(a, b []reflect.Type) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package reg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Box[T any] struct {
+	Item T
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type box[T any] struct{}
+
+func TestGenericNames(t *testing.T) {
+	registry := NewRegistry(WithDerivedNames())
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Box[Alpha]{}))
+	require.NoError(t, registry.Register(&Box[int]{}))
+	require.NoError(t, registry.Register(&Box[[]*Alpha]{}))
+	require.NoError(t, registry.Register(&Pair[string, map[string]Alpha]{}))
+	require.NoError(t, registry.Register(&Box[Box[Alpha]]{}))
+	for name, example := range map[string]interface{}{
+		"[app]Box[[app]Alpha]":                    &Box[Alpha]{},
+		"[app]Box[int]":                           &Box[int]{},
+		"[app]Box[[]*[app]Alpha]":                 &Box[[]*Alpha]{},
+		"[app]Pair[string,map[string][app]Alpha]": &Pair[string, map[string]Alpha]{},
+		"[app]Box[[app]Box[[app]Alpha]]":          &Box[Box[Alpha]]{},
+		"[][app]Box[[app]Alpha]":                  &[]Box[Alpha]{},
+	} {
+		actual, err := registry.NameFor(example)
+		require.NoError(t, err)
+		assert.Equal(t, name, actual)
+		item, err := registry.Make(name)
+		require.NoError(t, err, name)
+		assert.IsType(t, example, item, name)
+	}
+
+	// Full names are not registered for types registered after the alias.
+	for _, name := range []string{
+		packageName + "/Box[" + packageName + "/Alpha]",
+		"[app]Box[" + packageName + "/Alpha]",
+		packageName + "/Box[[app]Alpha]",
+	} {
+		_, err := registry.Make(name)
+		assert.ErrorIs(t, err, &ErrNotRegistered{Name: name}, name)
+	}
+	_, err := registry.Make("[app]Box[[app]Bravo]")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no registration for type named '[app]Box[[app]Bravo]'")
+	_, err = registry.Make("[app]Box[string]")
+	assert.Error(t, err)
+	_, err = registry.Make("[app]Other[[app]Alpha]")
+	assert.Error(t, err)
+}
+
+func TestGenericNamesAliasLater(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Box[Alpha]{}))
+	require.NoError(t, registry.Register(&Box[Box[Alpha]]{}))
+	name, err := registry.NameFor(&Box[Alpha]{})
+	require.NoError(t, err)
+	assert.Equal(t, packageName+"/Box["+packageName+"/Alpha]", name)
+
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	name, err = registry.NameFor(&Box[Alpha]{})
+	require.NoError(t, err)
+	assert.Equal(t, "[app]Box[[app]Alpha]", name)
+	name, err = registry.NameFor(&Box[Box[Alpha]]{})
+	require.NoError(t, err)
+	assert.Equal(t, "[app]Box[[app]Box[[app]Alpha]]", name)
+
+	// Any combination of registered names is accepted.
+	for _, name := range []string{
+		packageName + "/Box[" + packageName + "/Alpha]",
+		"[app]Box[" + packageName + "/Alpha]",
+		packageName + "/Box[[app]Alpha]",
+	} {
+		item, err := registry.Make(name)
+		require.NoError(t, err, name)
+		assert.IsType(t, &Box[Alpha]{}, item, name)
+	}
+}
+
+func TestGenericNamesRemoveAlias(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&Alpha{}))
+	require.NoError(t, registry.Register(&Box[Alpha]{}))
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	_, err := registry.Make(packageName + "/Box[[app]Alpha]")
+	require.NoError(t, err)
+
+	require.NoError(t, registry.(AliasRemover).RemoveAlias("app", false))
+	for _, name := range []string{"[app]Box[[app]Alpha]", packageName + "/Box[[app]Alpha]"} {
+		_, err = registry.Make(name)
+		assert.ErrorIs(t, err, &ErrNotRegistered{Name: name}, name)
+	}
+	name, err := registry.NameFor(&Box[Alpha]{})
+	require.NoError(t, err)
+	assert.Equal(t, packageName+"/Box["+packageName+"/Alpha]", name)
+	item, err := registry.Make(name)
+	require.NoError(t, err)
+	assert.IsType(t, &Box[Alpha]{}, item)
+
+	// Kept names still work.
+	require.NoError(t, registry.AddAlias("app", &Alpha{}))
+	require.NoError(t, registry.(AliasRemover).RemoveAlias("app", true))
+	for _, name := range []string{"[app]Box[[app]Alpha]", packageName + "/Box[[app]Alpha]"} {
+		item, err = registry.Make(name)
+		require.NoError(t, err, name)
+		assert.IsType(t, &Box[Alpha]{}, item, name)
+	}
+}
+
+func TestGenericNameErrors(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.Register(&Box[Alpha]{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no registration for type named '"+packageName+".Alpha'")
+	err = registry.Register(&Box[struct{}]{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no registration for type named 'struct {}'")
+	err = registry.Register(&box[int]{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is private")
+}
+
+func TestSplitTypeArgs(t *testing.T) {
+	assert.Equal(t, []string{"int"}, splitTypeArgs("int"))
+	assert.Equal(t, []string{"string", "map[string]x.Alpha", "x.Box[int,string]"},
+		splitTypeArgs("string,map[string]x.Alpha,x.Box[int,string]"))
+	assert.Equal(t, []string{"func(int, string)", "struct { A int; B int }"},
+		splitTypeArgs("func(int, string),struct { A int; B int }"))
+}
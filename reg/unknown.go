@@ -0,0 +1,49 @@
+package reg
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Unknown is a placeholder for serialized data with a type name that is not registered.
+// Lenient decoders (see codec.NewLenient and json.Converter.Lenient)
+// return Unknown objects instead of failing so that data can be passed through
+// by applications that don't (yet) know about some of the types therein.
+//
+// The Raw data is the entire serialized envelope including the type name.
+// Encoders write the Raw data back out exactly as it was read,
+// so the data must be re-encoded in the same format from which it was decoded.
+type Unknown struct {
+	// Name is the unregistered type name.
+	Name string
+
+	// Raw is the serialized envelope containing the type name and item data.
+	Raw []byte
+}
+
+// NewUnknown returns an Unknown placeholder with a copy of the raw data.
+func NewUnknown(name string, raw []byte) *Unknown {
+	return &Unknown{
+		Name: name,
+		Raw:  append(make([]byte, 0, len(raw)), raw...),
+	}
+}
+
+// Make sure the interfaces are satisfied at compile time.
+var _ json.Marshaler = &Unknown{}
+var _ fmt.Stringer = &Unknown{}
+
+// MarshalJSON returns the Raw data.
+// Note that encoding/json compacts the result of MarshalJSON
+// so whitespace in the Raw data may not be preserved.
+func (u *Unknown) MarshalJSON() ([]byte, error) {
+	if len(u.Raw) == 0 {
+		return nil, fmt.Errorf("no raw data for unknown type %s", u.Name)
+	}
+	return u.Raw, nil
+}
+
+// String returns a short description of the placeholder.
+func (u *Unknown) String() string {
+	return fmt.Sprintf("unknown type %s (%d bytes)", u.Name, len(u.Raw))
+}
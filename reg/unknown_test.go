@@ -0,0 +1,24 @@
+package reg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknown(t *testing.T) {
+	raw := []byte(`{"type":"[app]Charlie","data":{"Text":"unknown"}}`)
+	unknown := NewUnknown("[app]Charlie", raw)
+	raw[0] = '['
+	assert.Equal(t, byte('{'), unknown.Raw[0])
+	assert.Equal(t, "unknown type [app]Charlie (49 bytes)", unknown.String())
+
+	data, err := json.Marshal(map[string]interface{}{"item": unknown})
+	require.NoError(t, err)
+	assert.Equal(t, `{"item":{"type":"[app]Charlie","data":{"Text":"unknown"}}}`, string(data))
+	_, err = json.Marshal(&Unknown{Name: "empty"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no raw data for unknown type empty")
+}